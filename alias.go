@@ -0,0 +1,73 @@
+package pathtree
+
+import "errors"
+
+// Alias adds aliasPattern as another spelling of the pattern added as
+// existingPattern. Find returns the leaf of the existing pattern for paths
+// matching either, so they share their value, and Reverse of it gives the
+// existing pattern. The alias must have the same wildcards in the same order,
+// and has the priority of the existing pattern.
+//
+// Get returns the leaf of the alias itself, see AliasOf, and Update of the
+// alias updates the existing pattern. Removing the alias leaves the existing
+// pattern alone, while removing the existing pattern removes its aliases.
+func (n *Node) Alias(existingPattern, aliasPattern string) (*Leaf, error) {
+	primary := n.Get(existingPattern)
+	if primary == nil || primary.hidden {
+		return nil, errors.New("path not found")
+	}
+	if primary.alias != nil {
+		primary = primary.alias
+	}
+
+	p, err := n.parse(aliasPattern)
+	if err != nil {
+		return nil, err
+	}
+	slot, leaf := n.add(primary.order, p, 0, nil)
+	if *slot != nil {
+		return nil, &DuplicatePathError{aliasPattern, (*slot).Source()}
+	}
+	if !sameWildcardNames(leaf.Wildcards, primary.Wildcards) {
+		leaf.parent.prune()
+		return nil, errors.New("alias must have the same wildcards as " + existingPattern)
+	}
+
+	leaf.alias, leaf.source = primary, aliasPattern
+	primary.aliases = append(primary.aliases, leaf)
+	*slot = leaf
+	return leaf, nil
+}
+
+// AliasOf returns the leaf this leaf is an alias of, or nil.
+func (l *Leaf) AliasOf() *Leaf {
+	return l.alias
+}
+
+// Aliases returns the leafs of the aliases of this leaf.
+func (l *Leaf) Aliases() []*Leaf {
+	return l.aliases
+}
+
+// Removes the alias from the leaf it's an alias of.
+func (l *Leaf) unalias() {
+	primary := l.alias
+	for i, alias := range primary.aliases {
+		if alias == l {
+			primary.aliases = append(primary.aliases[:i:i], primary.aliases[i+1:]...)
+			break
+		}
+	}
+}
+
+func sameWildcardNames(a, b []Wildcard) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package pathtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/favicon.ico", 1)
+	n.Add("/static/*path", 2)
+	a1, err := n.Alias("/favicon.ico", "/static/favicon.ico")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	l3, _ := n.Add("/users/:id", 3)
+	a3, _ := n.Alias("/users/:id", "/u/:id")
+
+	if _, err := n.Alias("/users/:id", "/people/:name"); err == nil {
+		t.Errorf("Expected an error for different wildcards")
+	}
+	if n.Get("/people/:name") != nil || strings.Contains(n.String(), "people") {
+		t.Errorf("A failed alias should leave nothing behind")
+	}
+	if _, err := n.Alias("/missing", "/m"); err == nil {
+		t.Errorf("Expected an error for a missing pattern")
+	}
+	if _, err := n.Alias("/favicon.ico", "/static/favicon.ico"); err == nil {
+		t.Errorf("Expected an error for a duplicate alias")
+	}
+
+	if leaf, exp := n.Find("/static/favicon.ico"); leaf != l1 || exp != nil {
+		t.Errorf("Find of an alias (actual) %v %v", leaf, exp)
+	}
+	if leaf, exp := n.Find("/u/7"); leaf != l3 || !reflect.DeepEqual(exp, []string{"7"}) {
+		t.Errorf("Find of an alias with wildcards (actual) %v %v", leaf, exp)
+	}
+	found(t, n, "/static/app.js", []string{"app.js"}, 2)
+
+	if a1.AliasOf() != l1 || len(l1.Aliases()) != 1 || l1.Aliases()[0] != a1 {
+		t.Errorf("AliasOf (actual) %v %v", a1.AliasOf(), l1.Aliases())
+	}
+	reverse(t, n, l3, map[string]string{"id": "7"}, "/users/7", map[string]string{}, nil)
+
+	n.Update("/u/:id", 4)
+	found(t, n, "/users/7", []string{"7"}, 4)
+	if export := n.Export(); !strings.Contains(export, "/u/:id\t-> /users/:id\n") {
+		t.Errorf("Export should mark aliases:\n%s", export)
+	}
+
+	c := n.Clone()
+	if leaf, _ := c.Find("/u/7"); leaf == nil || leaf == l3 || leaf.Aliases()[0].AliasOf() != leaf {
+		t.Errorf("Clone should point aliases at the copies (actual) %v", leaf)
+	}
+
+	n.Remove("/u/:id")
+	notfound(t, n, "/u/7")
+	if len(l3.Aliases()) != 0 || a3.AliasOf() != l3 {
+		t.Errorf("Removing an alias should unlink it (actual) %v", l3.Aliases())
+	}
+	n.Remove("/favicon.ico")
+	found(t, n, "/static/favicon.ico", []string{"favicon.ico"}, 2)
+}
@@ -0,0 +1,75 @@
+package pathtree
+
+import "strings"
+
+// Ambiguity is a path element pattern which can split some path elements
+// between two of its wildcards in more than one way, eg. ":a;-:b" matching
+// "x-y-z". Find silently picks one of the ways.
+type Ambiguity struct {
+	Path        string // the patterns of the path elements up to this one, eg. "/:a;-:b"
+	Left, Right string // the names of the wildcards
+}
+
+// Ambiguities returns the path element patterns below this node which can split
+// path elements between wildcards in more than one way. Elements which can't
+// match anything are rejected by Add already.
+func (n *Node) Ambiguities() []Ambiguity {
+	var ambiguities []Ambiguity
+	n.ambiguities("", &ambiguities)
+	return ambiguities
+}
+
+func (n *Node) ambiguities(path string, ambiguities *[]Ambiguity) {
+	for _, edge := range n.sortedEdges() {
+		path := path + n.conf.sep + edge.repr
+		if edge.maxspan == 0 && edge.literals == nil {
+			for i, w := range edge.wildcards[:max(len(edge.wildcards)-1, 0)] {
+				if next := edge.wildcards[i+1]; edge.ambiguous(i) {
+					*ambiguities = append(*ambiguities, Ambiguity{path, w.Name, next.Name})
+				}
+			}
+		}
+		edge.node.ambiguities(path, ambiguities)
+	}
+}
+
+// Reports if the padding between wildcard i and the next one can be at more
+// than one position in the same path element.
+func (s *Segment) ambiguous(i int) bool {
+	w, next := &s.wildcards[i], &s.wildcards[i+1]
+	if w.fixed() || (next.fixed() && i+2 == len(s.wildcards)) {
+		return false
+	}
+	for _, pad := range s.padding[i+1] {
+		if pad == "" || w.contains(pad) || next.contains(pad) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports if all the values of the wildcard have the same length.
+func (w *Wildcard) fixed() bool {
+	if w.Enum == nil {
+		return w.Min > 0 && w.Min == w.Max
+	}
+	for _, v := range w.Enum {
+		if len(v) != len(w.Enum[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports if some value of the wildcard can contain pad.
+func (w *Wildcard) contains(pad string) bool {
+	if w.Enum == nil {
+		return w.Max == 0 || w.Max >= len(pad)
+	}
+	for _, v := range w.Enum {
+		if strings.Contains(v, pad) {
+			return true
+		}
+	}
+	return false
+}
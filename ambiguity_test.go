@@ -0,0 +1,33 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAmbiguities(t *testing.T) {
+	n := New()
+	n.Add("/:a;-:b", 1)
+	n.Add("/x/:c;:d", 2)
+	n.Add("/x/:[2,2]e;-:f/:g;-:h", 3)
+	n.Add("/y/:i(ab|cd);:j", 4)
+	n.Add("/y/:k(ab|c);:l;_:m", 5)
+	n.Add("/z/:n;-:[2,2]o", 6)
+	n.Add("/z/:p;-:[2,2]q;_:r", 7)
+	n.Add("/files/*path", 8)
+
+	expected := []Ambiguity{
+		{"/:a;-:b", "a", "b"},
+		{"/x/:[2,2]e;-:f/:g;-:h", "g", "h"},
+		{"/x/:c;:d", "c", "d"},
+		{"/y/:k(ab|c);:l;_:m", "k", "l"},
+		{"/y/:k(ab|c);:l;_:m", "l", "m"},
+		{"/z/:p;-:[2,2]q;_:r", "p", "q"},
+	}
+	if actual := n.Ambiguities(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Ambiguities (actual) %v != %v (expected)", actual, expected)
+	}
+	if actual := New().Ambiguities(); actual != nil {
+		t.Errorf("Ambiguities of an empty tree (actual) %v", actual)
+	}
+}
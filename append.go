@@ -0,0 +1,38 @@
+package pathtree
+
+// Append adds a value to the leaf of a path, adding the path if needed, so one
+// pattern can have several values, eg. layered handlers of a route. Unlike Add
+// a duplicate pattern isn't an error. The leaf's Value stays the first value,
+// so Find returns it as usual.
+func (n *Node) Append(key string, val interface{}) (leaf *Leaf, err error) {
+	if leaf = n.Get(key); leaf == nil {
+		if leaf, err = n.Add(key, val); err != nil {
+			return nil, err
+		}
+		leaf.values = []interface{}{val}
+		return leaf, nil
+	}
+
+	if leaf.alias != nil {
+		leaf = leaf.alias
+	}
+	if leaf.values == nil {
+		leaf.values = []interface{}{leaf.Load()}
+	}
+	leaf.values = append(leaf.values, val)
+	return leaf, nil
+}
+
+// FindValues finds a given path like Find and returns the values of its leaf in
+// the order they were appended. For leafs added without Append it's the leaf's
+// Value alone.
+func (n *Node) FindValues(key string) (leaf *Leaf, expansions []string, values []interface{}) {
+	leaf, expansions = n.Find(key)
+	if leaf == nil {
+		return nil, nil, nil
+	}
+	if leaf.values == nil {
+		return leaf, expansions, []interface{}{leaf.Load()}
+	}
+	return leaf, expansions, leaf.values
+}
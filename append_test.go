@@ -0,0 +1,41 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	n := New()
+	n.Append("/users/:id", "auth")
+	n.Append("/users/:id", "log")
+	if _, err := n.Append("/users/:id", "user"); err != nil {
+		t.Errorf("Append of a duplicate pattern (actual) %v", err)
+	}
+	n.Add("/posts/:id", "post")
+	n.Append("/posts/:id", "more")
+	n.Add("/files/*path", "file")
+	if _, err := n.Append("users", "x"); err == nil {
+		t.Errorf("Expected an error for an invalid path")
+	}
+
+	leaf, exp, values := n.FindValues("/users/7")
+	if leaf == nil || leaf.Value != "auth" || !reflect.DeepEqual(exp, []string{"7"}) || !reflect.DeepEqual(values, []interface{}{"auth", "log", "user"}) {
+		t.Errorf("FindValues (actual) %v %v %v", leaf, exp, values)
+	}
+	if _, _, values := n.FindValues("/posts/7"); !reflect.DeepEqual(values, []interface{}{"post", "more"}) {
+		t.Errorf("FindValues after Add (actual) %v", values)
+	}
+	if _, _, values := n.FindValues("/files/a"); !reflect.DeepEqual(values, []interface{}{"file"}) {
+		t.Errorf("FindValues without Append (actual) %v", values)
+	}
+	if leaf, exp, values := n.FindValues("/missing"); leaf != nil || exp != nil || values != nil {
+		t.Errorf("Should not have found: /missing")
+	}
+
+	c := n.Clone()
+	c.Append("/users/:id", "clone")
+	if _, _, values := n.FindValues("/users/7"); len(values) != 3 {
+		t.Errorf("Append to a clone changed the original (actual) %v", values)
+	}
+}
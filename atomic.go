@@ -0,0 +1,32 @@
+package pathtree
+
+// The value of a leaf set with Store.
+type storedValue struct {
+	val interface{}
+}
+
+// Store sets the value of the leaf so that it can be read with Load by
+// concurrent Finds, to reload values without changing the tree. Setting Value
+// directly while the tree is in use is a data race, and once Store was called
+// Value is no longer updated, though Update and the methods returning values,
+// like FindNS, go through Store and Load.
+func (l *Leaf) Store(val interface{}) {
+	l.stored.Store(storedValue{val})
+}
+
+// Load returns the value last set with Store, or else Value.
+func (l *Leaf) Load() interface{} {
+	if stored, ok := l.stored.Load().(storedValue); ok {
+		return stored.val
+	}
+	return l.Value
+}
+
+// Sets the value of the leaf, through Store once it was used.
+func (l *Leaf) set(val interface{}) {
+	if _, ok := l.stored.Load().(storedValue); ok {
+		l.Store(val)
+		return
+	}
+	l.Value = val
+}
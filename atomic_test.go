@@ -0,0 +1,71 @@
+package pathtree
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStoreLoad(t *testing.T) {
+	n := New()
+	leaf, _ := n.Add("/scripts/:name", 0)
+
+	if v := leaf.Load(); v != 0 {
+		t.Errorf("Load before Store (actual) %v", v)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 1000; i++ {
+			leaf.Store(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		last := 0
+		for i := 0; i < 1000; i++ {
+			l, _ := n.Find("/scripts/a")
+			v := l.Load().(int)
+			if v < last {
+				t.Errorf("Load went back from %d to %d", last, v)
+			}
+			last = v
+		}
+	}()
+	wg.Wait()
+
+	if v := leaf.Load(); v != 1000 {
+		t.Errorf("Load after Store (actual) %v", v)
+	}
+	leaf.Store(nil)
+	if v := leaf.Load(); v != nil {
+		t.Errorf("Load after storing nil (actual) %v", v)
+	}
+}
+
+func TestStoreUsedEverywhere(t *testing.T) {
+	n := New()
+	leaf, _ := n.Add("/scripts/:name", 0)
+	leaf.Store(1)
+
+	if _, _, v, _ := n.FindWeighted("/scripts/a", nil); v != 1 {
+		t.Errorf("FindWeighted (actual) %v", v)
+	}
+	if _, _, v := n.FindNS("api", "/scripts/a"); v != 1 {
+		t.Errorf("FindNS (actual) %v", v)
+	}
+	if Value[int](leaf) != 1 {
+		t.Errorf("Value (actual) %v", Value[int](leaf))
+	}
+	if routes := n.Routes(); len(routes) != 1 || routes[0].Value != 1 {
+		t.Errorf("Routes (actual) %v", routes)
+	}
+
+	if _, err := n.Update("/scripts/:name", 2); err != nil || leaf.Load() != 2 {
+		t.Errorf("Load after Update (actual) %v %v", leaf.Load(), err)
+	}
+	if _, _, values := n.FindValues("/scripts/a"); len(values) != 1 || values[0] != 2 {
+		t.Errorf("FindValues (actual) %v", values)
+	}
+}
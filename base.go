@@ -0,0 +1,20 @@
+package pathtree
+
+import (
+	"strings"
+)
+
+// WithBasePath mounts the tree under a base path, like "/api/v2", and returns
+// it. Find then only matches paths below the base path and strips it before
+// matching, while Reverse prepends it to the paths it generates. Patterns are
+// still added without the base path. Leading and trailing slashes of prefix
+// are normalized, so "api/v2/" is the same as "/api/v2", and "/" or "" remove
+// the base path.
+func (n *Node) WithBasePath(prefix string) *Node {
+	prefix = strings.Trim(prefix, n.conf.sep)
+	if prefix != "" {
+		prefix = n.conf.sep + prefix
+	}
+	n.base = prefix
+	return n
+}
@@ -0,0 +1,28 @@
+package pathtree
+
+import (
+	"testing"
+)
+
+func TestWithBasePath(t *testing.T) {
+	n := New().WithBasePath("api/v2/")
+
+	l1, _ := n.Add("/", 1)
+	l2, _ := n.Add("/users/:id", 2)
+	l3, _ := n.Add("/files/*path", 3)
+
+	found(t, n, "/api/v2", nil, 1)
+	found(t, n, "/api/v2/", nil, 1)
+	found(t, n, "/api/v2/users/7", []string{"7"}, 2)
+	found(t, n, "/api/v2/files/a/b", []string{"a/b"}, 3)
+	notfound(t, n, "/users/7")
+	notfound(t, n, "/api/v2users/7")
+	notfound(t, n, "/api/v1/users/7")
+
+	reverse(t, n, l1, map[string]string{}, "/api/v2/", map[string]string{}, nil)
+	reverse(t, n, l2, map[string]string{"id": "7"}, "/api/v2/users/7", map[string]string{}, nil)
+	reverse(t, n, l3, map[string]string{"path": "a/b"}, "/api/v2/files/a/b", map[string]string{}, nil)
+
+	n.WithBasePath("/")
+	found(t, n, "/users/7", []string{"7"}, 2)
+}
@@ -0,0 +1,147 @@
+package pathtree
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindError is an error binding an expansion to a field of a struct.
+type BindError struct {
+	Field string // the name of the struct field
+	Name  string // the name of the wildcard
+	Err   error
+}
+
+func (e *BindError) Error() string {
+	return "field " + e.Field + " (" + e.Name + "): " + e.Err.Error()
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// FindBind finds a given path like Find and binds its expansions into the
+// struct pointed to by dst, see MatchResult.Bind. Returns a nil leaf and no
+// error if nothing was found.
+func (n *Node) FindBind(key string, dst interface{}) (*Leaf, error) {
+	m := n.Match(key)
+	if m == nil {
+		return nil, nil
+	}
+	return m.Leaf, m.Bind(dst)
+}
+
+// Bind sets the fields of the struct pointed to by dst from the expansions of
+// the wildcards with the same name, like encoding/json does with keys:
+//
+//	var p struct {
+//		ID   int    `path:"id"`
+//		Page int    `path:"page,optional"`
+//		Path string `path:"path"`
+//		Dirs []string `path:"path"`
+//		Skip string `path:"-"`
+//	}
+//
+// Fields with a path tag are required unless it includes ",optional", other
+// exported fields are bound if a wildcard matches their name ignoring case.
+// Fields may be strings, integers, unsigned integers, floats or bools, and star
+// expansions may also be bound to a []string of their path elements. Returns
+// all errors joined, each a *BindError.
+func (m *MatchResult) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("destination must be a pointer to a struct")
+	}
+	v = v.Elem()
+
+	var errs []error
+	fields := v.Type()
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, required := field.Name, false
+		if tag, ok := field.Tag.Lookup("path"); ok {
+			if tag == "-" {
+				continue
+			}
+			opts := strings.Split(tag, ",")
+			if opts[0] != "" {
+				name = opts[0]
+			}
+			required = true
+			for _, opt := range opts[1:] {
+				if opt == "optional" {
+					required = false
+				}
+			}
+		}
+
+		index := m.index(name, !required && field.Tag.Get("path") == "")
+		if index == -1 {
+			if required {
+				errs = append(errs, &BindError{field.Name, name, errors.New("missing required value")})
+			}
+			continue
+		}
+
+		if err := m.bind(v.Field(i), index); err != nil {
+			errs = append(errs, &BindError{field.Name, name, err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Returns the index of the expansion of the wildcard with the given name.
+func (m *MatchResult) index(name string, fold bool) int {
+	for i, wildcard := range m.Leaf.WildcardNames() {
+		if i < len(m.Expansions) && (wildcard == name || (fold && strings.EqualFold(wildcard, name))) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MatchResult) bind(field reflect.Value, index int) error {
+	value := m.Expansions[index]
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String || !m.Leaf.isStar() || index != len(m.Leaf.Wildcards)-1 {
+			return errors.New("only star expansions can be bound to a slice of strings")
+		}
+		field.Set(reflect.ValueOf(strings.Split(value, m.Leaf.parent.conf.sep)).Convert(field.Type()))
+	default:
+		return errors.New("unsupported field type " + field.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package pathtree
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	n := New()
+	n.Add("/users/:id/posts/:slug/:page;.:ratio/*path", 1)
+	n.Add("/flags/:enabled/:id", 2)
+
+	var p struct {
+		ID     int      `path:"id"`
+		Slug   string   `path:"slug"`
+		Page   uint8    `path:"page"`
+		Ratio  float64  `path:"ratio"`
+		Path   string   `path:"path"`
+		Dirs   []string `path:"path"`
+		Missed string   `path:"missed,optional"`
+		Skip   string   `path:"-"`
+		Other  string
+		slug   string
+	}
+	leaf, err := n.FindBind("/users/7/posts/hello/3.5/a/b", &p)
+	if err != nil || leaf == nil || leaf.Value != 1 {
+		t.Fatalf("FindBind (actual) %v %v", leaf, err)
+	}
+	if p.ID != 7 || p.Slug != "hello" || p.Page != 3 || p.Ratio != 5 || p.Path != "a/b" || !reflect.DeepEqual(p.Dirs, []string{"a", "b"}) {
+		t.Errorf("Bound (actual) %+v", p)
+	}
+
+	var q struct {
+		Enabled bool
+		ID      int8
+	}
+	if _, err := n.FindBind("/flags/true/12", &q); err != nil || !q.Enabled || q.ID != 12 {
+		t.Errorf("Bound by field name (actual) %+v %v", q, err)
+	}
+
+	var r struct {
+		ID      int8   `path:"id"`
+		Enabled bool   `path:"enabled"`
+		Name    string `path:"name"`
+	}
+	_, err = n.FindBind("/flags/maybe/1000", &r)
+	var berr *BindError
+	if !errors.As(err, &berr) || berr.Field != "ID" || !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Expected a range error for ID, got %v", err)
+	}
+	if err == nil || err.Error() != `field ID (id): strconv.ParseInt: parsing "1000": value out of range
+field Enabled (enabled): strconv.ParseBool: parsing "maybe": invalid syntax
+field Name (name): missing required value` {
+		t.Errorf("Errors (actual) %v", err)
+	}
+
+	if leaf, err := n.FindBind("/missing", &r); leaf != nil || err != nil {
+		t.Errorf("FindBind of a missing path (actual) %v %v", leaf, err)
+	}
+	if err := n.Match("/flags/true/1").Bind(r); err == nil {
+		t.Errorf("Expected an error binding to a non pointer")
+	}
+}
+
+func TestMatchResult(t *testing.T) {
+	n := New()
+	n.Add("/:a/:b/:a", 1)
+
+	m := n.Match("/x/y/z")
+	if v, ok := m.Get("a"); !ok || v != "x" {
+		t.Errorf("Get (actual) %v %v", v, ok)
+	}
+	if params := m.Params(); !reflect.DeepEqual(params, map[string]string{"a": "x", "b": "y"}) {
+		t.Errorf("Params (actual) %v", params)
+	}
+	if n.Match("/x") != nil {
+		t.Errorf("Should not have matched: /x")
+	}
+}
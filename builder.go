@@ -0,0 +1,98 @@
+package pathtree
+
+import (
+	"errors"
+	"strings"
+)
+
+// MustAdd adds a path like Add and returns the node for chaining. It panics if
+// the path can't be added, for route tables that are fixed at compile time.
+func (n *Node) MustAdd(key string, val interface{}) *Node {
+	if _, err := n.Add(key, val); err != nil {
+		panic("pathtree: " + key + ": " + err.Error())
+	}
+	return n
+}
+
+// PatternError is an error adding a pattern to a tree.
+type PatternError struct {
+	Pattern string
+	Err     error
+}
+
+func (e *PatternError) Error() string {
+	return e.Pattern + ": " + e.Err.Error()
+}
+
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// Builder collects routes for a tree, with groups of routes sharing a prefix.
+//
+//	var b pathtree.Builder
+//	b.Route("/", home)
+//	b.Group("/users/:id", func(b *pathtree.Builder) {
+//		b.Route("/", user)
+//		b.Route("/posts", posts)
+//	})
+//	tree, err := b.Build()
+type Builder struct {
+	prefix string
+	routes *[]route
+}
+
+type route struct {
+	pattern string
+	val     interface{}
+}
+
+// Route adds a pattern and its value, below the prefix of the group if any.
+func (b *Builder) Route(pattern string, val interface{}) *Builder {
+	if b.routes == nil {
+		b.routes = new([]route)
+	}
+	*b.routes = append(*b.routes, route{joinPattern(b.prefix, pattern), val})
+	return b
+}
+
+// Group calls fn with a builder whose routes are below prefix. The prefix may
+// contain wildcards, and groups may be nested.
+func (b *Builder) Group(prefix string, fn func(*Builder)) *Builder {
+	if b.routes == nil {
+		b.routes = new([]route)
+	}
+	fn(&Builder{prefix: joinPattern(b.prefix, prefix), routes: b.routes})
+	return b
+}
+
+// Build adds all the routes to a new tree in the order they were given.
+// Returns the tree and an error joining a *PatternError for every route that
+// couldn't be added.
+func (b *Builder) Build() (*Node, error) {
+	n := New()
+	var errs []error
+	if b.routes != nil {
+		for _, r := range *b.routes {
+			if _, err := n.Add(r.pattern, r.val); err != nil {
+				errs = append(errs, &PatternError{r.pattern, err})
+			}
+		}
+	}
+	return n, errors.Join(errs...)
+}
+
+// Joins a group prefix and a pattern, keeping the trailing slash of pattern.
+func joinPattern(prefix, pattern string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if pattern == "" || pattern == "/" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix + pattern
+	}
+	if pattern[0] != '/' {
+		pattern = "/" + pattern
+	}
+	return prefix + pattern
+}
@@ -0,0 +1,51 @@
+package pathtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMustAdd(t *testing.T) {
+	n := New().MustAdd("/", 1).MustAdd("/a/:b", 2)
+	found(t, n, "/a/c", []string{"c"}, 2)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a duplicate path")
+		}
+	}()
+	n.MustAdd("/a/:b", 3)
+}
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.Route("/", 1)
+	b.Group("/users/:id;", func(b *Builder) {
+		b.Route("/", 2)
+		b.Route("posts/:post", 3)
+		b.Group("/files/", func(b *Builder) {
+			b.Route("/*path", 4)
+		})
+	})
+	b.Route("/users/:id/posts/:post", 5)
+	b.Route("/", 6)
+
+	n, err := b.Build()
+	found(t, n, "/", nil, 1)
+	found(t, n, "/users/7/", []string{"7"}, 2)
+	found(t, n, "/users/7/posts/8", []string{"7", "8"}, 3)
+	found(t, n, "/users/7/files/a/b", []string{"7", "a/b"}, 4)
+
+	var perr *PatternError
+	if !errors.As(err, &perr) || perr.Pattern != "/users/:id/posts/:post" {
+		t.Errorf("Expected a pattern error for /users/:id/posts/:post, got %v", err)
+	}
+	expected := `/users/:id/posts/:post: duplicate path "/users/:id/posts/:post": already added as "/users/:id;/posts/:post"` + "\n" +
+		`/: duplicate path "/": already added as "/"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Errors (actual) %v", err)
+	}
+	if leaf := n.Get("/users/:id/"); leaf == nil || leaf.Pattern() != "/users/:id/" {
+		t.Errorf("Group with trailing slash (actual) %v", leaf)
+	}
+}
@@ -0,0 +1,83 @@
+package pathtree
+
+// BulkBuilder builds a tree allocating its nodes, edges and leafs in chunks
+// rather than one by one, so a tree with many patterns is made of far fewer
+// objects for the garbage collector to track.
+//
+// The tree returned by Finish can still be changed, with nodes, edges and
+// leafs added afterwards allocated one by one again. A chunk is only freed
+// once nothing in it is used, so removing patterns frees little memory: trees
+// built in bulk are best treated as immutable, and rebuilt rather than changed.
+type BulkBuilder struct {
+	tree *Node
+}
+
+// NewBulkBuilder returns a builder for a tree created with the options, with
+// chunks sized for about expectedLeaves patterns.
+func NewBulkBuilder(expectedLeaves int, opts ...Option) *BulkBuilder {
+	tree := New(opts...)
+	tree.conf.arena = newArena(expectedLeaves)
+	return &BulkBuilder{tree: tree}
+}
+
+// Add adds a path and its value to the tree, see Node.Add.
+func (b *BulkBuilder) Add(key string, val interface{}) (*Leaf, error) {
+	return b.tree.Add(key, val)
+}
+
+// Finish returns the tree built. The builder can't be used afterwards.
+func (b *BulkBuilder) Finish() *Node {
+	tree := b.tree
+	tree.conf.arena = nil
+	b.tree = nil
+	return tree
+}
+
+// Allocates nodes, edges and leafs from chunks. A nil arena allocates them one
+// by one.
+type arena struct {
+	size  int    // the number of items in a chunk
+	nodes []Node // the current chunk of nodes
+	edges []Edge // the current chunk of edges
+	leafs []Leaf // the current chunk of leafs
+}
+
+func newArena(expectedLeaves int) *arena {
+	return &arena{size: max(expectedLeaves, 64)}
+}
+
+// Returns a new zero node.
+func (a *arena) node() *Node {
+	if a == nil {
+		return new(Node)
+	}
+	if len(a.nodes) == cap(a.nodes) {
+		a.nodes = make([]Node, 0, a.size)
+	}
+	a.nodes = a.nodes[:len(a.nodes)+1]
+	return &a.nodes[len(a.nodes)-1]
+}
+
+// Returns a new zero edge.
+func (a *arena) edge() *Edge {
+	if a == nil {
+		return new(Edge)
+	}
+	if len(a.edges) == cap(a.edges) {
+		a.edges = make([]Edge, 0, a.size)
+	}
+	a.edges = a.edges[:len(a.edges)+1]
+	return &a.edges[len(a.edges)-1]
+}
+
+// Returns a new zero leaf.
+func (a *arena) leaf() *Leaf {
+	if a == nil {
+		return new(Leaf)
+	}
+	if len(a.leafs) == cap(a.leafs) {
+		a.leafs = make([]Leaf, 0, a.size)
+	}
+	a.leafs = a.leafs[:len(a.leafs)+1]
+	return &a.leafs[len(a.leafs)-1]
+}
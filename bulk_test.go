@@ -0,0 +1,63 @@
+package pathtree
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBulkBuilder(t *testing.T) {
+	b := NewBulkBuilder(10)
+	for i := 0; i < 100; i++ {
+		if _, err := b.Add(fmt.Sprintf("/a%d_/:id/b", i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Add("/a1_/:id/b", 0); err == nil {
+		t.Errorf("Expected duplicate path error")
+	}
+	n := b.Finish()
+	n.Add("/c/*rest", "c")
+
+	for i := 0; i < 100; i += 7 {
+		found(t, n, fmt.Sprintf("/a%d_/x/b", i), []string{"x"}, i)
+	}
+	found(t, n, "/c/d", []string{"d"}, "c")
+	if n.conf.arena != nil {
+		t.Errorf("Finish should stop allocating in chunks")
+	}
+}
+
+// The paths added by the build benchmarks, only built once they run.
+var bulkPaths = sync.OnceValue(func() []string {
+	paths := make([]string, 100000)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("/api/v%d/resource%d/items", i%10, i)
+	}
+	return paths
+})
+
+func BenchmarkBuildAdd(b *testing.B) {
+	paths := bulkPaths()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n := New()
+		for _, path := range paths {
+			n.Add(path, nil)
+		}
+	}
+}
+
+func BenchmarkBuildBulk(b *testing.B) {
+	paths := bulkPaths()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bulk := NewBulkBuilder(len(paths))
+		for _, path := range paths {
+			bulk.Add(path, nil)
+		}
+		bulk.Finish()
+	}
+}
@@ -0,0 +1,147 @@
+package pathtree
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize returns the normalized form of a pattern, so that patterns Add
+// treats the same are equal as strings, as long as they use the same wildcard
+// names. In the normalized form:
+//
+//   - every wildcard is terminated by ';', even at the end of an element
+//   - bounds are written [n] when the minimum and maximum are the same, and
+//     left out when both are 0
+//   - enumerated values are sorted and duplicates dropped
+//   - duplicate padding alternatives are dropped, but their order is kept as
+//     the first alternative present in a path is the one used
+//
+// The trailing slash is kept, as it's significant with WithStrictSlash.
+func Canonicalize(pattern string) (string, error) {
+	if len(pattern) == 0 || pattern[0] != '/' {
+		return "", errors.New("Path must begin with /")
+	}
+	elements, slashend := splitPath(pattern, "/")
+	for i, el := range elements {
+		if len(el) > 0 && el[0] == '*' && i != len(elements)-1 {
+			return "", errors.New("star must be the last path element")
+		}
+		elements[i] = canonicalElement(el)
+	}
+
+	canonical := "/" + strings.Join(elements, "/")
+	if slashend && len(elements) > 0 {
+		canonical += "/"
+	}
+	return canonical, nil
+}
+
+// Returns the normalized form of a path element.
+func canonicalElement(el string) string {
+	if len(el) > 0 && el[0] == '*' {
+		return el
+	}
+	if len(el) > 0 && el[len(el)-1] == ';' {
+		el = el[:len(el)-1]
+	}
+	if min, max, name, ok := parseSpan(el); ok {
+		return formatSpan(min, max, name)
+	}
+
+	el, maxlen := cutMaxLength(el)
+	paddings, wildcards, wildend := parseElement(el)
+	return formatMaxLength(maxlen) + formatElement(paddings, wildcards, wildend)
+}
+
+// Returns the normalized form of a parsed path element.
+func formatElement(paddings [][]string, wildcards []Wildcard, wildend bool) string {
+	var b strings.Builder
+	for i, wildcard := range wildcards {
+		b.WriteString(canonicalPadding(paddings[i]))
+		if wildcard.Numeric {
+			b.WriteString("#" + wildcard.canonical() + ";")
+			continue
+		}
+		b.WriteString(":" + wildcard.canonical() + ";")
+	}
+	if !wildend {
+		b.WriteString(canonicalPadding(paddings[len(paddings)-1]))
+	}
+	return b.String()
+}
+
+func canonicalPadding(pads []string) string {
+	seen := make(map[string]bool, len(pads))
+	unique := pads[:0:0]
+	for _, pad := range pads {
+		if !seen[pad] {
+			seen[pad] = true
+			unique = append(unique, pad)
+		}
+	}
+	return strings.Join(unique, "|")
+}
+
+// Returns the normalized form of the wildcard, without ':' or ';'.
+func (w *Wildcard) canonical() string {
+	var s string
+	switch {
+	case w.Min == w.Max && w.Min != 0:
+		s = "[" + strconv.Itoa(w.Min) + "]"
+	case w.Min != 0 || w.Max != 0:
+		s = "[" + strconv.Itoa(w.Min) + "," + strconv.Itoa(w.Max) + "]"
+	}
+	s += w.Name
+	if w.Enum != nil {
+		enum := append([]string(nil), w.Enum...)
+		sort.Strings(enum)
+		unique := enum[:0]
+		for i, value := range enum {
+			if i == 0 || value != enum[i-1] {
+				unique = append(unique, value)
+			}
+		}
+		s += "(" + strings.Join(unique, "|") + ")"
+	}
+	return s
+}
+
+// CanonicalShape returns the normalized form of a pattern like Canonicalize,
+// with wildcard names replaced by their position in the pattern and without
+// a trailing slash, so that patterns matching the same paths have the same
+// shape, eg. "/users/:id/:[0,0]x/" and "/users/:uid;/:y" are both
+// "/users/:0;/:1;". References to defined wildcards are kept as they are.
+// Returns "" if the pattern is invalid.
+func CanonicalShape(pattern string) string {
+	canonical, err := Canonicalize(pattern)
+	if err != nil {
+		return ""
+	}
+
+	elements, _ := splitPath(canonical, "/")
+	position := 0
+	for i, el := range elements {
+		if len(el) > 0 && el[0] == '*' {
+			elements[i] = "*" + strconv.Itoa(position)
+			continue
+		}
+		if min, max, _, ok := parseSpan(el); ok {
+			elements[i] = formatSpan(min, max, strconv.Itoa(position))
+			position++
+			continue
+		}
+
+		el, maxlen := cutMaxLength(strings.TrimSuffix(el, ";"))
+		paddings, wildcards, wildend := parseElement(el)
+		for key := range wildcards {
+			if !strings.HasPrefix(wildcards[key].Name, "@") {
+				wildcards[key].Name = strconv.Itoa(position)
+			}
+			position++
+		}
+		elements[i] = formatMaxLength(maxlen) + formatElement(paddings, wildcards, wildend)
+	}
+	return "/" + strings.Join(elements, "/")
+}
@@ -0,0 +1,77 @@
+package pathtree
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct{ pattern, canonical string }{
+		{"/", "/"},
+		{"/users/:id", "/users/:id;"},
+		{"/users/:id;/", "/users/:id;/"},
+		{"/:[3,3]x/:[3]y/:[0,0]z/:[2,5]w", "/:[3]x;/:[3]y;/:z;/:[2,5]w;"},
+		{"/:v(b|a|b);.json", "/:v(a|b);.json"},
+		{"/img_:name;.jpg|.jpeg|.jpg", "/img_:name;.jpg|.jpeg"},
+		{"/a|a/*rest", "/a/*rest"},
+	}
+	for _, c := range cases {
+		if canonical, err := Canonicalize(c.pattern); err != nil || canonical != c.canonical {
+			t.Errorf("%s: (actual) %q %v != %q (expected)", c.pattern, canonical, err, c.canonical)
+		}
+		if again, _ := Canonicalize(c.canonical); again != c.canonical {
+			t.Errorf("%s: not idempotent: %q", c.canonical, again)
+		}
+	}
+
+	for _, pattern := range []string{"", "users", "/*a/b"} {
+		if _, err := Canonicalize(pattern); err == nil {
+			t.Errorf("%q: expected an error", pattern)
+		}
+	}
+}
+
+func TestCanonicalPatterns(t *testing.T) {
+	n := New(WithCanonicalPatterns())
+
+	leaf, _ := n.Add("/users/:[3,3]id/:v(y|x)", 1)
+	if pattern := leaf.Pattern(); pattern != "/users/:[3]id;/:v(x|y);" {
+		t.Errorf("Pattern (actual) %q", pattern)
+	}
+	if _, err := n.Add("/users/:[3]id;/:v(x|y|x)", 2); err == nil {
+		t.Errorf("Expected an equivalent pattern to be a duplicate")
+	}
+	if n.Get("/users/:[3,3]id/:v(x|y)") != leaf {
+		t.Errorf("Get should find equivalent patterns")
+	}
+	found(t, n, "/users/abc/x", []string{"abc", "x"}, 1)
+}
+
+func TestCanonicalShape(t *testing.T) {
+	equivalent := [][]string{
+		{"/users/:id/:[0,0]x/", "/users/:uid;/:y", "/users/:a/:b;/"},
+		{"/:[3,3]x;_:v(b|a)", "/:[3]y;_:w(a|b|a);"},
+		{"/files/:dir/*path", "/files/:d/*rest/"},
+	}
+	for _, patterns := range equivalent {
+		shape := CanonicalShape(patterns[0])
+		for _, pattern := range patterns[1:] {
+			if other := CanonicalShape(pattern); other != shape {
+				t.Errorf("%s: (actual) %q != %q (expected)", pattern, other, shape)
+			}
+		}
+	}
+
+	if shape := CanonicalShape("/users/:id/:[0,0]x/"); shape != "/users/:0;/:1;" {
+		t.Errorf("Shape (actual) %q", shape)
+	}
+	if shape := CanonicalShape("/files/:dir/*path"); shape != "/files/:0;/*1" {
+		t.Errorf("Shape of a star (actual) %q", shape)
+	}
+	if shape := CanonicalShape("/"); shape != "/" {
+		t.Errorf("Shape of the root (actual) %q", shape)
+	}
+	if shape := CanonicalShape("users"); shape != "" {
+		t.Errorf("Shape of an invalid pattern (actual) %q", shape)
+	}
+	if a, b := CanonicalShape("/:a/:b"), CanonicalShape("/:[2]a/:b"); a == b {
+		t.Errorf("Patterns with different bounds have the same shape %q", a)
+	}
+}
@@ -0,0 +1,135 @@
+package pathtree
+
+// Clone returns a deep copy of the tree below this node, which becomes the root
+// of the copy. Values and metadata values are shared, everything else is
+// copied so either tree can be changed without affecting the other.
+func (n *Node) Clone() *Node {
+	conf := *n.conf
+	conf.arena = nil
+	if n.conf.decoders != nil {
+		conf.decoders = make(map[string]valueDecoder, len(n.conf.decoders))
+		for name, decode := range n.conf.decoders {
+			conf.decoders[name] = decode
+		}
+	}
+	if n.conf.defs != nil {
+		conf.defs = make(map[string]Wildcard, len(n.conf.defs))
+		for name, w := range n.conf.defs {
+			conf.defs[name] = w
+		}
+	}
+	leafs := make(map[*Leaf]*Leaf)
+	c := n.clone(nil, &conf, leafs)
+	if n.conf.fallbacks != nil {
+		conf.fallbacks = make(map[string]*Leaf, len(n.conf.fallbacks))
+		for key, leaf := range n.conf.fallbacks {
+			conf.fallbacks[key] = leaf.clone(c)
+		}
+	}
+
+	// Point aliases at the copies of the leafs they are aliases of
+	c.walk(func(l *Leaf) {
+		if primary, ok := leafs[l.alias]; ok {
+			l.alias = primary
+			primary.aliases = append(primary.aliases, l)
+		}
+	})
+	return c
+}
+
+// Clones the node, adding the copies of the leafs of the tree to leafs.
+func (n *Node) clone(parent *Edge, conf *config, leafs map[*Leaf]*Leaf) *Node {
+	c := &Node{
+		edges:   make(map[string]*Edge, len(n.edges)),
+		conf:    conf,
+		leafs:   n.leafs,
+		parent:  parent,
+		base:    n.base,
+		counts:  n.counts,
+		compact: n.compact,
+		nostar:  n.nostar,
+	}
+	c.leaf = n.leaf.clone(c)
+	c.star = n.star.clone(c)
+	if n.leaf != nil {
+		leafs[n.leaf] = c.leaf
+	}
+	if n.star != nil {
+		leafs[n.star] = c.star
+	}
+
+	c.sorted = make([]*Edge, 0, len(n.sorted))
+	for _, edge := range n.sorted {
+		e := new(Edge)
+		*e = *edge
+		e.parent = c
+		e.node = edge.node.clone(e, conf, leafs)
+		e.stats = conf.newProfile()
+		c.edges[e.repr] = e
+		c.sorted = append(c.sorted, e)
+		if e.fixed > 0 {
+			c.indexLength(e)
+		}
+		if e.prefix != "" {
+			c.indexPrefix(e)
+		}
+	}
+	c.padded.rescan(c.sorted)
+	return c
+}
+
+func (l *Leaf) clone(parent *Node) *Leaf {
+	if l == nil {
+		return nil
+	}
+
+	c := new(Leaf)
+	*c = *l
+	c.parent = parent
+	c.aliases = nil
+	if l.choices != nil {
+		c.choices = append([]WeightedValue(nil), l.choices...)
+	}
+	if l.values != nil {
+		c.values = append([]interface{}(nil), l.values...)
+	}
+	if l.meta != nil {
+		c.meta = make(map[string]interface{}, len(l.meta))
+		for key, v := range l.meta {
+			c.meta[key] = v
+		}
+	}
+	if l.transforms != nil {
+		c.transforms = append([]func(string) string(nil), l.transforms...)
+	}
+	if l.tags != nil {
+		c.tags = make(map[string]bool, len(l.tags))
+		for tag := range l.tags {
+			c.tags[tag] = true
+		}
+	}
+	if l.methods != nil {
+		c.methods = make(map[string]interface{}, len(l.methods))
+		for method, v := range l.methods {
+			c.methods[method] = v
+		}
+	}
+	if l.namespaces != nil {
+		c.namespaces = make(map[string]interface{}, len(l.namespaces))
+		for ns, v := range l.namespaces {
+			c.namespaces[ns] = v
+		}
+	}
+	if l.failure != nil {
+		c.failure = l.failure.clone(parent)
+		c.failure.failed = c
+	}
+	if l.versions != nil {
+		c.versions = make([]*Leaf, len(l.versions))
+		for i, version := range l.versions {
+			c.versions[i] = version.clone(parent)
+			c.versions[i].head = c
+		}
+	}
+	return c
+}
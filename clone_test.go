@@ -0,0 +1,30 @@
+package pathtree
+
+import (
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	n := exportTree()
+	c := n.Clone()
+
+	if c.Export() != n.Export() || c.String() != n.String() {
+		t.Errorf("Clone differs:\n%s!=\n%s", c.Export(), n.Export())
+	}
+
+	c.Add("/new", 10)
+	c.Remove("/a")
+	c.Update("/b", 11)
+	found(t, n, "/a", nil, 6)
+	found(t, n, "/b", nil, 7)
+	notfound(t, n, "/new")
+	found(t, c, "/new", nil, 10)
+	found(t, c, "/b", nil, 11)
+	found(t, c, "/users/7/posts", []string{"7"}, 3)
+
+	leaf, _ := c.FindVersion("/users/7", 1)
+	if leaf == nil || leaf.Value != 9 || leaf.root() != c {
+		t.Errorf("Cloned version (actual) %v", leaf)
+	}
+	reverse(t, c, leaf, map[string]string{"id": "7"}, "/users/7", map[string]string{}, nil)
+}
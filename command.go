@@ -0,0 +1,141 @@
+package pathtree
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// CommandTree routes command line arguments using the same patterns as paths,
+// with words separated by spaces instead of '/':
+//
+//	remote add :name *args
+type CommandTree struct {
+	root *Node
+}
+
+// CommandError is returned by Dispatch when no command matches the arguments.
+type CommandError struct {
+	Args        []string // the arguments dispatched
+	Depth       int      // the index of the first argument no command accepted
+	Suggestions []string // the literal words accepted at that depth, closest first
+}
+
+func (e *CommandError) Error() string {
+	msg := "unknown command"
+	if e.Depth < len(e.Args) {
+		msg += " " + strings.Join(e.Args[:e.Depth+1], " ")
+	}
+	if len(e.Suggestions) > 0 {
+		msg += ", did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return msg
+}
+
+// NewCommandTree returns a new command tree.
+func NewCommandTree() *CommandTree {
+	return &CommandTree{root: New()}
+}
+
+// AddCommand adds a command and its associated handler to the tree.
+func (c *CommandTree) AddCommand(command string, handler interface{}) error {
+	words := strings.Fields(command)
+	if len(words) == 0 {
+		return errors.New("empty command")
+	}
+	_, err := c.root.Add("/"+strings.Join(words, "/"), handler)
+	return err
+}
+
+// Dispatch finds the command matching args and returns its handler with the
+// arguments bound to each wildcard. Star wildcards are bound to the remaining
+// arguments joined with spaces. Returns a *CommandError if nothing matches.
+func (c *CommandTree) Dispatch(args []string) (handler interface{}, params map[string]string, err error) {
+	leaf, expansions := c.root.FindElements(args)
+	if leaf == nil {
+		return nil, nil, c.miss(args)
+	}
+
+	if leaf.isStar() {
+		expansions[len(expansions)-1] = strings.Join(args[leaf.depth():], " ")
+	}
+	params = make(map[string]string, len(expansions))
+	for i, value := range expansions {
+		params[leaf.Wildcards[i].Name] = value
+	}
+	return leaf.Load(), params, nil
+}
+
+// Follows the literal words of args as far as possible and suggests the
+// literal words accepted where it stopped.
+func (c *CommandTree) miss(args []string) *CommandError {
+	node, depth := c.root, 0
+	for ; depth < len(args); depth++ {
+		next := node.literal(args[depth])
+		if next == nil {
+			break
+		}
+		node = next
+	}
+
+	var words []string
+	for _, edge := range node.sortedEdges() {
+		if len(edge.wildcards) == 0 {
+			words = append(words, edge.padding[0]...)
+		}
+	}
+
+	if depth < len(args) {
+		arg := args[depth]
+		limit := len(arg)/3 + 1
+		var close []string
+		for _, word := range words {
+			if levenshtein(arg, word) <= limit || strings.HasPrefix(word, arg) {
+				close = append(close, word)
+			}
+		}
+		sort.Slice(close, func(i, j int) bool {
+			di, dj := levenshtein(arg, close[i]), levenshtein(arg, close[j])
+			return di < dj || (di == dj && close[i] < close[j])
+		})
+		words = close
+	} else {
+		sort.Strings(words)
+	}
+
+	return &CommandError{Args: args, Depth: depth, Suggestions: words}
+}
+
+// Returns the node of the literal edge accepting element, if any.
+func (n *Node) literal(element string) *Node {
+	for _, edge := range n.sortedEdges() {
+		if len(edge.wildcards) != 0 {
+			continue
+		}
+		for _, pad := range edge.padding[0] {
+			if pad == element {
+				return edge.node
+			}
+		}
+	}
+	return nil
+}
+
+func levenshtein(a, b string) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			prev, row[j] = row[j], min(row[j]+1, row[j-1]+1, prev+cost)
+		}
+	}
+	return row[len(b)]
+}
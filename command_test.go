@@ -0,0 +1,46 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandTree(t *testing.T) {
+	c := NewCommandTree()
+
+	c.AddCommand("remote add :name :url", 1)
+	c.AddCommand("remote remove :name", 2)
+	c.AddCommand("remote", 3)
+	c.AddCommand("commit *args", 4)
+	c.AddCommand("status", 5)
+
+	handler, params, err := c.Dispatch([]string{"remote", "add", "origin", "https://example.com/repo.git"})
+	if err != nil || handler != 1 || !reflect.DeepEqual(params, map[string]string{"name": "origin", "url": "https://example.com/repo.git"}) {
+		t.Errorf("remote add: (actual) %v %v %v", handler, params, err)
+	}
+
+	handler, params, err = c.Dispatch([]string{"commit", "-m", "a message"})
+	if err != nil || handler != 4 || !reflect.DeepEqual(params, map[string]string{"args": "-m a message"}) {
+		t.Errorf("commit: (actual) %v %v %v", handler, params, err)
+	}
+
+	handler, _, err = c.Dispatch([]string{"remote"})
+	if err != nil || handler != 3 {
+		t.Errorf("remote: (actual) %v %v", handler, err)
+	}
+
+	_, _, err = c.Dispatch([]string{"remote", "rmove", "origin"})
+	cerr, ok := err.(*CommandError)
+	if !ok || cerr.Depth != 1 || !reflect.DeepEqual(cerr.Suggestions, []string{"remove"}) {
+		t.Errorf("remote rmove: (actual) %v", err)
+	}
+	if err != nil && err.Error() != "unknown command remote rmove, did you mean remove?" {
+		t.Errorf("remote rmove: message (actual) %s", err.Error())
+	}
+
+	_, _, err = c.Dispatch([]string{"stats"})
+	cerr, ok = err.(*CommandError)
+	if !ok || cerr.Depth != 0 || !reflect.DeepEqual(cerr.Suggestions, []string{"status"}) {
+		t.Errorf("stats: (actual) %v", err)
+	}
+}
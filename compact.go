@@ -0,0 +1,113 @@
+package pathtree
+
+import (
+	"slices"
+	"strings"
+)
+
+// Compact merges chains of literal path elements leading to nodes without a
+// leaf, star or other edges into a single edge, eg. "/a" -> "/b" -> "/c" into
+// "a/b/c". This reduces the number of nodes and map lookups along the chain.
+//
+// Only literals without alternatives are merged. Adding to the tree afterwards
+// splits merged edges again where the new path branches off.
+func (n *Node) Compact() {
+	for _, edge := range slices.Clone(n.sortedEdges()) {
+		if edge.compactable() {
+			n.deleteEdge(edge.repr)
+			for edge.compactable() {
+				var next *Edge
+				for _, next = range edge.node.edges {
+				}
+				literals := append(edge.elements(), next.elements()...)
+				repr := strings.Join(literals, n.conf.sep)
+				edge = &Edge{Segment: Segment{padding: [][]string{{repr}}}, node: next.node, minorder: edge.minorder, parent: n, repr: repr, literals: literals, stats: n.conf.newProfile()}
+				edge.node.parent = edge
+			}
+			n.setEdge(edge)
+			n.compact = true
+		}
+		edge.node.Compact()
+	}
+}
+
+// Reports if this edge can be merged with the only edge of its node.
+func (e *Edge) compactable() bool {
+	if !e.plain() || e.node.leaf != nil || e.node.star != nil || len(e.node.edges) != 1 {
+		return false
+	}
+	for _, next := range e.node.edges {
+		return next.plain()
+	}
+	return false
+}
+
+// Reports if this edge is a non empty literal without alternatives.
+func (e *Edge) plain() bool {
+	return e.literals != nil || (len(e.wildcards) == 0 && len(e.padding) == 1 && len(e.padding[0]) == 1 && e.padding[0][0] != "")
+}
+
+// Returns the path elements matched by a literal edge.
+func (e *Edge) elements() []string {
+	if e.literals != nil {
+		return e.literals
+	}
+	return []string{e.repr}
+}
+
+// Returns the number of path elements matched by this edge.
+func (e *Edge) width() int {
+	if e.literals != nil {
+		return len(e.literals)
+	}
+	if e.maxspan > 0 {
+		return e.minspan
+	}
+	return 1
+}
+
+// Splits off the first element of a compacted edge starting with el, so a path
+// can be added branching off of it.
+func (n *Node) expand(el string) (edge *Edge, ok bool) {
+	if !n.compact {
+		return nil, false
+	}
+	for _, edge := range n.sortedEdges() {
+		if edge.literals == nil || edge.literals[0] != el {
+			continue
+		}
+
+		n.deleteEdge(edge.repr)
+		node := n.addEdge(Segment{padding: [][]string{{el}}}, el, edge.minorder)
+		if rest := edge.literals[1:]; len(rest) == 1 {
+			edge.repr, edge.literals = rest[0], nil
+		} else {
+			edge.repr, edge.literals = strings.Join(rest, n.conf.sep), rest
+		}
+		edge.padding = [][]string{{edge.repr}}
+		edge.parent = node
+		node.setEdge(edge)
+		node.compact = edge.literals != nil
+		return n.edges[el], true
+	}
+	return nil, false
+}
+
+// Matches the remaining literals of a compacted edge and continues from here.
+func (n *Node) findLiterals(literals, elements, exp []string, q *query) (leaf *Leaf, expansions []string) {
+	for i, literal := range literals {
+		if !n.conf.equal(literal, elements[i]) {
+			return nil, nil
+		}
+	}
+	return n.find(elements[len(literals):], exp, q)
+}
+
+// Returns the number of nodes in the tree below and including this node.
+func (n *Node) nodes() int {
+	count := 1
+	for _, edge := range n.edges {
+		count += edge.node.nodes()
+	}
+	return count
+}
@@ -0,0 +1,83 @@
+package pathtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	n := New()
+
+	n.Add("/", 1)
+	n.Add("/a/b/c", 2)
+	n.Add("/a/b/c/d/:e", 3)
+	n.Add("/x/y/z/*rest", 4)
+	n.Add("/p|q/r/s", 5)
+
+	if nodes := n.nodes(); nodes != 12 {
+		t.Errorf("Nodes before Compact (actual) %d != 12 (expected)", nodes)
+	}
+	n.Compact()
+	if nodes := n.nodes(); nodes != 7 {
+		t.Errorf("Nodes after Compact (actual) %d != 7 (expected)", nodes)
+	}
+
+	found(t, n, "/", nil, 1)
+	found(t, n, "/a/b/c", nil, 2)
+	found(t, n, "/a/b/c/", nil, 2)
+	found(t, n, "/a/b/c/d/e", []string{"e"}, 3)
+	found(t, n, "/x/y/z/1/2", []string{"1/2"}, 4)
+	found(t, n, "/q/r/s", nil, 5)
+	notfound(t, n, "/a/b")
+	notfound(t, n, "/a/b/x")
+	notfound(t, n, "/x/y/z")
+
+	leaf, _ := n.Find("/a/b/c/d/e")
+	reverse(t, n, leaf, map[string]string{"e": "f"}, "/a/b/c/d/f", map[string]string{}, nil)
+	if leaf.Pattern() != "/a/b/c/d/:e" {
+		t.Errorf("Pattern (actual) %s != /a/b/c/d/:e (expected)", leaf.Pattern())
+	}
+
+	// Adding splits the compacted edges again
+	if _, err := n.Add("/a/b/c", 6); err == nil {
+		t.Errorf("Expected duplicate path error")
+	}
+	n.Add("/a/b", 7)
+	n.Add("/x/y/w", 8)
+	found(t, n, "/a/b", nil, 7)
+	found(t, n, "/a/b/c", nil, 2)
+	found(t, n, "/x/y/w", nil, 8)
+	found(t, n, "/x/y/z/1", []string{"1"}, 4)
+}
+
+func deepTree(depth, width int) *Node {
+	n := New()
+	for i := 0; i < width; i++ {
+		key := ""
+		for j := 0; j < depth; j++ {
+			key += fmt.Sprintf("/dir%d_%d", i, j)
+		}
+		n.Add(key, i)
+	}
+	return n
+}
+
+func BenchmarkCompact(b *testing.B) {
+	for _, compact := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compact=%v", compact), func(b *testing.B) {
+			n := deepTree(10, 20)
+			if compact {
+				n.Compact()
+			}
+			key := ""
+			for j := 0; j < 10; j++ {
+				key += fmt.Sprintf("/dir7_%d", j)
+			}
+			b.ReportMetric(float64(n.nodes()), "nodes")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				n.Find(key)
+			}
+		})
+	}
+}
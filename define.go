@@ -0,0 +1,51 @@
+package pathtree
+
+import "errors"
+
+// DefineWildcard defines the constraints of the wildcard references ":@name"
+// in the patterns of the tree, so patterns sharing them needn't repeat them:
+//
+//	n.DefineWildcard("uuid", Wildcard{Min: 36, Max: 36})
+//	n.Add("/users/:@uuid/posts/:@uuid;.json", users)
+//
+// The wildcard takes the name of the definition, and patterns keep the
+// reference form. Definitions can be added at any time, but not changed once
+// something was added to the tree, and patterns referencing an undefined
+// wildcard can't be added.
+func (n *Node) DefineWildcard(name string, w Wildcard) error {
+	if _, ok := n.conf.defs[name]; ok && n.leafs > 0 {
+		return errors.New("wildcard @" + name + " can't be redefined after the first Add")
+	}
+	if n.conf.defs == nil {
+		n.conf.defs = make(map[string]Wildcard)
+	}
+	w.Name = name
+	n.conf.defs[name] = w
+	return nil
+}
+
+// Checks the wildcard references of a pattern are defined.
+func (c *config) checkDefined(p *Pattern) error {
+	if !p.defined {
+		return nil
+	}
+	for _, el := range p.parsed {
+		for _, wildcard := range el.segment.wildcards {
+			if len(wildcard.Name) > 0 && wildcard.Name[0] == '@' {
+				if _, ok := c.defs[wildcard.Name[1:]]; !ok {
+					return errors.New("undefined wildcard " + wildcard.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Replaces the wildcard references of the segment with their definition.
+func (s *Segment) resolve(c *config) {
+	for i, wildcard := range s.wildcards {
+		if len(wildcard.Name) > 0 && wildcard.Name[0] == '@' {
+			s.wildcards[i] = c.defs[wildcard.Name[1:]]
+		}
+	}
+}
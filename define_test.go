@@ -0,0 +1,44 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefineWildcard(t *testing.T) {
+	n := New()
+	n.DefineWildcard("uuid", Wildcard{Min: 4, Max: 4})
+	n.DefineWildcard("kind", Wildcard{Enum: []string{"a", "b"}})
+
+	l1, err := n.Add("/users/:@uuid/:@kind;.json", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := n.Add("/:@slug", 2); err == nil {
+		t.Errorf("Expected an error for an undefined wildcard")
+	}
+
+	found(t, n, "/users/abcd/a.json", []string{"abcd", "a"}, 1)
+	notfound(t, n, "/users/abc/a.json")
+	notfound(t, n, "/users/abcd/c.json")
+
+	if pattern := l1.Pattern(); pattern != "/users/:@uuid/:@kind;.json" {
+		t.Errorf("Pattern (actual) %s", pattern)
+	}
+	expected := []Wildcard{{Name: "uuid", Min: 4, Max: 4}, {Name: "kind", Enum: []string{"a", "b"}}}
+	if !reflect.DeepEqual(l1.Wildcards, expected) {
+		t.Errorf("Wildcards (actual) %v != %v (expected)", l1.Wildcards, expected)
+	}
+	reverse(t, n, l1, map[string]string{"uuid": "abcd", "kind": "b"}, "/users/abcd/b.json", map[string]string{}, nil)
+
+	if err := n.DefineWildcard("uuid", Wildcard{Min: 36, Max: 36}); err == nil {
+		t.Errorf("Expected an error redefining a wildcard after Add")
+	}
+	if err := n.DefineWildcard("slug", Wildcard{Min: 1}); err != nil {
+		t.Errorf("Unexpected error defining a new wildcard: %v", err)
+	}
+	if _, err := n.Add("/:@slug", 2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	notfound(t, n, "/")
+}
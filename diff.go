@@ -0,0 +1,112 @@
+package pathtree
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Diff compares the routes of two trees, returning the patterns only b has as
+// added, those only a has as removed, and those whose value or the pattern
+// they are an alias of differ as changed. A removed and an added pattern that
+// only differ in wildcard names or constraints are reported as changed if no
+// other route of either tree has the same shape, eg.
+// "/users/:[1,8]id; -> /users/:[1,16]id;: value 1 -> 2". Patterns are in
+// their canonical form, followed by the version range of versioned leafs, and
+// each list is sorted. Values are compared with reflect.DeepEqual.
+func Diff(a, b *Node) (added, removed, changed []string) {
+	before, beforeShapes := diffRoutes(a)
+	after, afterShapes := diffRoutes(b)
+	for pattern, old := range before {
+		if route, ok := after[pattern]; ok {
+			if change := old.change(route); change != "" {
+				changed = append(changed, change)
+			}
+			delete(before, pattern)
+			delete(after, pattern)
+		}
+	}
+
+	// A route whose pattern changed is paired up with the one of the other tree
+	// with the same shape, if neither tree has another route of that shape
+	byShape := make(map[string]*diffRoute)
+	for _, route := range after {
+		if afterShapes[route.shape] == 1 && beforeShapes[route.shape] == 1 {
+			byShape[route.shape] = route
+		}
+	}
+	for pattern, old := range before {
+		if route, ok := byShape[old.shape]; ok {
+			changed = append(changed, old.change(route))
+			delete(before, pattern)
+			delete(after, route.pattern)
+		}
+	}
+
+	for _, old := range before {
+		removed = append(removed, old.pattern)
+	}
+	for _, route := range after {
+		added = append(added, route.pattern)
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
+
+// A route compared by Diff.
+type diffRoute struct {
+	Route
+	pattern string // the canonical pattern and version range
+	shape   string // the shape of the pattern without constraints, and version range
+}
+
+// Describes how the route changed into route, or "" if it didn't.
+func (r *diffRoute) change(route *diffRoute) string {
+	change := r.pattern
+	if route.pattern != r.pattern {
+		change += " -> " + route.pattern
+	}
+	if !reflect.DeepEqual(route.Value, r.Value) || route.Alias != r.Alias {
+		change += ": value " + r.value() + " -> " + route.value()
+	}
+	if change == r.pattern {
+		return ""
+	}
+	return change
+}
+
+// Describes the value of the route.
+func (r *diffRoute) value() string {
+	if r.Alias != "" {
+		return "alias of " + r.Alias
+	}
+	return fmt.Sprintf("%v", r.Value)
+}
+
+// Matches the constraints of wildcards in a canonical pattern.
+var diffConstraints = regexp.MustCompile(`(:)\[[0-9,]*\]|\([^)]*\)`)
+
+// Returns the routes of the tree by their canonical pattern and version range,
+// and the number of routes of each shape.
+func diffRoutes(n *Node) (routes map[string]*diffRoute, shapes map[string]int) {
+	routes, shapes = make(map[string]*diffRoute), make(map[string]int)
+	for _, route := range n.Routes() {
+		pattern, err := Canonicalize(route.Pattern)
+		if err != nil {
+			pattern = route.Pattern
+		}
+		shape := CanonicalShape(diffConstraints.ReplaceAllString(pattern, "$1"))
+		if len(route.Versions) == 2 {
+			versions := " v" + strconv.Itoa(route.Versions[0]) + "-" + strconv.Itoa(route.Versions[1])
+			shape += versions
+			pattern += versions
+		}
+		routes[pattern] = &diffRoute{route, pattern, shape}
+		shapes[shape]++
+	}
+	return routes, shapes
+}
@@ -0,0 +1,58 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := New()
+	a.Add("/", 1)
+	a.Add("/users/:[1,8]id", 2)
+	a.Add("/files/*path", 3)
+	a.Add("/old", 4)
+	a.Add("/posts/:post", 5)
+	a.Alias("/posts/:post", "/p/:post")
+	a.AddVersioned("/api/:v", 6, 1, 2)
+
+	b := New()
+	b.Add("/", 1)
+	b.Add("/users/:[1,16]id", 2)
+	b.Add("/files/*name", "three")
+	b.Add("/new/:id;", 7)
+	b.Add("/posts/:post", 5)
+	b.Add("/articles/:post", 8)
+	b.Alias("/articles/:post", "/p/:post")
+	b.AddVersioned("/api/:v", 6, 1, 2)
+	b.AddVersioned("/api/:v", 9, 3, 3)
+
+	added, removed, changed := Diff(a, b)
+	if expected := []string{"/api/:v; v3-3", "/articles/:post;", "/new/:id;"}; !reflect.DeepEqual(added, expected) {
+		t.Errorf("Added (actual) %q != %q (expected)", added, expected)
+	}
+	if expected := []string{"/old"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("Removed (actual) %q != %q (expected)", removed, expected)
+	}
+	expected := []string{
+		"/files/*path -> /files/*name: value 3 -> three",
+		"/p/:post;: value alias of /posts/:post -> alias of /articles/:post",
+		"/users/:[1,8]id; -> /users/:[1,16]id;",
+	}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Changed (actual) %q != %q (expected)", changed, expected)
+	}
+
+	if added, removed, changed := Diff(b, b.Clone()); added != nil || removed != nil || changed != nil {
+		t.Errorf("Diff of a clone (actual) %q %q %q", added, removed, changed)
+	}
+
+	// Routes of the same shape are only paired up if they are the only ones
+	c, d := New(), New()
+	c.Add("/c/:[2]a", 1)
+	c.Add("/c/:[3]b", 2)
+	d.Add("/c/:[2]a", 1)
+	added, removed, changed = Diff(c, d)
+	if expected := []string{"/c/:[3]b;"}; added != nil || !reflect.DeepEqual(removed, expected) || changed != nil {
+		t.Errorf("Diff of routes of the same shape (actual) %q %q %q != %q (expected removed)", added, removed, changed, expected)
+	}
+}
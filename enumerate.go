@@ -0,0 +1,80 @@
+package pathtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// Enumerate returns a concrete path matching each leaf of the tree no deeper
+// than maxDepth path elements (0 for any depth), ordered by the order the leafs
+// were added, for fuzzing or endpoint discovery.
+//
+// Padding uses its first alternative, wildcards with enumerated values their
+// first value and wildcards with bounds a value of their minimum length, or 1.
+// Stars and other wildcards use the value returned by sample, or their name if
+// sample is nil. Paths that don't find their leaf, because sample returned a
+// value the wildcard doesn't allow or a leaf added earlier matches them, are
+// left out.
+func (n *Node) Enumerate(maxDepth int, sample func(Wildcard) string) []string {
+	if sample == nil {
+		sample = func(w Wildcard) string { return w.Name }
+	}
+
+	var leafs []*Leaf
+	n.walk(func(l *Leaf) {
+		if l.head == nil {
+			leafs = append(leafs, l)
+		}
+	})
+	sort.Slice(leafs, func(i, j int) bool { return leafs[i].order < leafs[j].order })
+
+	var paths []string
+	for _, leaf := range leafs {
+		depth := leaf.depth()
+		if leaf.isStar() {
+			depth++
+		}
+		if maxDepth > 0 && depth > maxDepth {
+			continue
+		}
+
+		path := leaf.enumerate(sample)
+		if found, _ := n.Find(path); found == leaf {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Returns a concrete path matching the leaf.
+func (l *Leaf) enumerate(sample func(Wildcard) string) string {
+	root := l.root()
+	sep := root.conf.sep
+	path := root.base
+
+	for _, edge := range l.edges() {
+		path += sep
+		for key, wildcard := range edge.wildcards {
+			path += edge.padding[key][0]
+			switch {
+			case wildcard.Enum != nil:
+				path += wildcard.Enum[0]
+			case wildcard.Min != 0 || wildcard.Max != 0:
+				path += strings.Repeat("a", max(wildcard.Min, 1))
+			default:
+				path += sample(wildcard)
+			}
+		}
+		if !edge.wildend {
+			path += edge.padding[len(edge.padding)-1][0]
+		}
+	}
+
+	if l.isStar() {
+		path += sep + sample(l.Wildcards[len(l.Wildcards)-1])
+	}
+	if l.slashend || path == root.base {
+		path += sep
+	}
+	return path
+}
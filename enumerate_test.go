@@ -0,0 +1,34 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnumerate(t *testing.T) {
+	n := New()
+	n.Add("/", 1)
+	n.Add("/users/:[3,8]id/", 2)
+	n.Add("/img_:name;.jpg|.jpeg", 3)
+	n.Add("/v/:v(x|y)/files/*path", 4)
+	n.Add("/a/b/c", 5)
+	n.Compact()
+
+	sample := func(w Wildcard) string { return "<" + w.Name + ">" }
+	expected := []string{"/", "/users/aaa/", "/img_<name>.jpg", "/v/x/files/<path>", "/a/b/c"}
+	if paths := n.Enumerate(0, sample); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Enumerate (actual) %q != %q (expected)", paths, expected)
+	}
+
+	expected = []string{"/", "/users/aaa/", "/img_name.jpg"}
+	if paths := n.Enumerate(2, nil); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Enumerate up to depth 2 (actual) %q", paths)
+	}
+
+	n = New(WithRequireNonEmptyWildcards()).WithBasePath("/api")
+	n.Add("/:id", 1)
+	n.Add("/", 2)
+	if paths := n.Enumerate(0, func(Wildcard) string { return "" }); !reflect.DeepEqual(paths, []string{"/api/"}) {
+		t.Errorf("Enumerate should leave out paths not accepted (actual) %q", paths)
+	}
+}
@@ -0,0 +1,123 @@
+package pathtree
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Route is a leaf of the tree as exported by Export and MarshalJSON.
+type Route struct {
+	Pattern   string      `json:"pattern"`
+	Source    string      `json:"source,omitempty"`
+	Order     int         `json:"order"`
+	Wildcards []Wildcard  `json:"wildcards,omitempty"`
+	Value     interface{} `json:"value"`
+	Alias     string      `json:"alias,omitempty"`
+	Tags      []string    `json:"tags,omitempty"`
+	Versions  []int       `json:"versions,omitempty"`
+}
+
+// Routes returns every leaf in the tree in the order of Walk. Source is only
+// set if it differs from Pattern. Aliases have the pattern they are an alias
+// of as Alias, and no value. Versions has the minimum and maximum version of
+// versioned leafs.
+func (n *Node) Routes() []Route {
+	var routes []Route
+	n.Walk(func(leaf *Leaf) {
+		route := Route{Pattern: leaf.Pattern(), Order: leaf.order, Wildcards: leaf.Wildcards, Value: leaf.Load(), Tags: leaf.Tags()}
+		if leaf.Source() != route.Pattern {
+			route.Source = leaf.Source()
+		}
+		if leaf.alias != nil {
+			route.Value, route.Alias = nil, leaf.alias.Pattern()
+		}
+		if min, max, ok := leaf.Versions(); ok {
+			route.Versions = []int{min, max}
+		}
+		routes = append(routes, route)
+	})
+	return routes
+}
+
+// Export returns the leafs of the tree as text, one per line in the order of
+// Walk, with the pattern and value separated by a tab, or for aliases "->"
+// and the pattern they are an alias of. Tags follow after another tab, sorted
+// and each prefixed with '#'. Trees with the same patterns, values and tags
+// export the same text.
+func (n *Node) Export() string {
+	var b strings.Builder
+	n.Walk(func(leaf *Leaf) {
+		pattern := leaf.Pattern()
+		if min, max, ok := leaf.Versions(); ok {
+			pattern += "@" + versionRange(min, max)
+		}
+		if leaf.alias != nil {
+			fmt.Fprintf(&b, "%s\t-> %s", pattern, leaf.alias.Pattern())
+		} else {
+			fmt.Fprintf(&b, "%s\t%v", pattern, leaf.Load())
+		}
+		if tags := leaf.Tags(); tags != nil {
+			b.WriteString("\t#" + strings.Join(tags, " #"))
+		}
+		b.WriteString("\n")
+	})
+	return b.String()
+}
+
+// MarshalJSON encodes the routes of the tree as a JSON array, for ImportJSON.
+// Values other than strings, numbers, bools and nil are encoded as an
+// EncodedValue.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	routes := n.Routes()
+	if routes == nil {
+		routes = []Route{}
+	}
+	for i := range routes {
+		value, err := encodeValue(routes[i].Value)
+		if err != nil {
+			return nil, &PatternError{routes[i].Pattern, err}
+		}
+		routes[i].Value = value
+	}
+	return json.Marshal(routes)
+}
+
+// String returns the tree as indented text, one node per line, with edges
+// sorted by pattern.
+func (n *Node) String() string {
+	var b strings.Builder
+	b.WriteString(n.conf.sep)
+	n.dump(&b, 0)
+	return b.String()
+}
+
+func (n *Node) dump(b *strings.Builder, depth int) {
+	if n.leaf != nil {
+		n.leaf.each(func(leaf *Leaf) { dumpLeaf(b, leaf) })
+	}
+	b.WriteString("\n")
+
+	indent := strings.Repeat("  ", depth+1)
+	if n.star != nil {
+		b.WriteString(indent + starElement(n.star.Wildcards[len(n.star.Wildcards)-1].Name))
+		n.star.each(func(leaf *Leaf) { dumpLeaf(b, leaf) })
+		b.WriteString("\n")
+	}
+	for _, edge := range n.sortedEdges() {
+		b.WriteString(indent + edge.repr)
+		edge.node.dump(b, depth+1)
+	}
+}
+
+func dumpLeaf(b *strings.Builder, leaf *Leaf) {
+	fmt.Fprintf(b, " [%d", leaf.order)
+	if min, max, ok := leaf.Versions(); ok {
+		b.WriteString("@" + versionRange(min, max))
+	}
+	if leaf.alias != nil {
+		fmt.Fprintf(b, "] -> %s", leaf.alias.Pattern())
+		return
+	}
+	fmt.Fprintf(b, "] %v", leaf.Load())
+}
@@ -0,0 +1,107 @@
+package pathtree
+
+import (
+	"regexp"
+	"testing"
+)
+
+func exportTree() *Node {
+	n := New()
+	n.Add("/", 1)
+	n.Add("/users/:id", 2)
+	n.Add("/users/:id/posts", 3)
+	n.Add("/files/*path", 4)
+	n.Add("/archive|history/:year", 5)
+	n.Add("/a", 6)
+	n.Add("/b", 7)
+	n.Add("/c", 8)
+	n.AddVersioned("/users/:id", 9, 1, 2)
+	return n
+}
+
+func TestExport(t *testing.T) {
+	n := exportTree()
+
+	expected := "/\t1\n" +
+		"/a\t6\n" +
+		"/archive|history/:year\t5\n" +
+		"/b\t7\n" +
+		"/c\t8\n" +
+		"/files/*path\t4\n" +
+		"/users/:id\t2\n" +
+		"/users/:id@[1,2]\t9\n" +
+		"/users/:id/posts\t3\n"
+	if export := n.Export(); export != expected {
+		t.Errorf("Export (actual)\n%s!= (expected)\n%s", export, expected)
+	}
+
+	expected = "/ [1] 1\n" +
+		"  a [6] 6\n" +
+		"  archive|history\n" +
+		"    :year [5] 5\n" +
+		"  b [7] 7\n" +
+		"  c [8] 8\n" +
+		"  files\n" +
+		"    *path [4] 4\n" +
+		"  users\n" +
+		"    :id [2] 2 [2@[1,2]] 9\n" +
+		"      posts [3] 3\n"
+	if dump := n.String(); dump != expected {
+		t.Errorf("String (actual)\n%s!= (expected)\n%s", dump, expected)
+	}
+
+	json, err := n.MarshalJSON()
+	if err != nil || string(json[:60]) != `[{"pattern":"/","order":1,"value":1},{"pattern":"/a","order"` {
+		t.Errorf("MarshalJSON (actual) %s %v", json, err)
+	}
+}
+
+func TestExportDeterministic(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		a, b := exportTree(), exportTree()
+		if a.Export() != b.Export() || a.String() != b.String() {
+			t.Fatalf("Exports of the same tree differ:\n%s\n%s", a.Export(), b.Export())
+		}
+		ja, _ := a.MarshalJSON()
+		jb, _ := b.MarshalJSON()
+		if string(ja) != string(jb) {
+			t.Fatalf("JSON of the same tree differs:\n%s\n%s", ja, jb)
+		}
+	}
+}
+
+func TestDumpIndependentOfAddOrder(t *testing.T) {
+	patterns := []string{"/", "/b/:x", "/a/c", "/a/b/c/d", "/:y/z", "/a|b/:[2]v", "/c/*rest", "/a/:[3]w"}
+	var dumps []string
+	for i := 0; i < len(patterns); i++ {
+		n := New()
+		for j := range patterns {
+			n.Add(patterns[(i+j)%len(patterns)], nil)
+		}
+		n.Remove("/a/b/c/d")
+		n.Add("/a/b/c/d", nil)
+		n.Compact()
+		n.Add("/a/b/x", nil)
+		dumps = append(dumps, regexp.MustCompile(` \[\d+\]`).ReplaceAllString(n.String(), ""))
+
+		checkSorted(t, n)
+	}
+	for _, dump := range dumps[1:] {
+		if dump != dumps[0] {
+			t.Fatalf("Dumps of trees with the same patterns differ:\n%s\n%s", dumps[0], dump)
+		}
+	}
+}
+
+// Checks the sorted edges of every node are its edges.
+func checkSorted(t *testing.T, n *Node) {
+	if len(n.sorted) != len(n.edges) {
+		t.Errorf("%d sorted edges != %d edges", len(n.sorted), len(n.edges))
+	}
+	for i, edge := range n.sorted {
+		if n.edges[edge.repr] != edge || (i > 0 && n.sorted[i-1].repr >= edge.repr) {
+			t.Errorf("Edge %s out of place in the sorted edges", edge.repr)
+		}
+		checkSorted(t, edge.node)
+	}
+}
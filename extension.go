@@ -0,0 +1,66 @@
+package pathtree
+
+import "strings"
+
+// Finds the elements with the extension of the last one cut off, returning the
+// extension along with the leaf found.
+func (n *Node) findExtension(elements []string, q *query) (leaf *Leaf, expansions []string, ext string) {
+	last := len(elements) - 1
+	if last < 0 {
+		return nil, nil, ""
+	}
+	base, ext := cutExtension(elements[last])
+	if ext == "" {
+		return nil, nil, ""
+	}
+
+	// Star expansions sliced from the path must end before the extension
+	if q != nil && q.path != "" {
+		path := q.path
+		q.path = path[:len(path)-len(ext)-1]
+		defer func() { q.path = path }()
+	}
+	if leaf, expansions = n.find(append(elements[:last:last], base), nil, q); leaf == nil {
+		return nil, nil, ""
+	}
+	return leaf, expansions, ext
+}
+
+// Finds the elements with a literal matching the last one, extension included,
+// which wins over patterns matching it without the extension, like
+// "/robots.txt" does over "/:page".
+func (n *Node) findExact(elements []string, q *query) (leaf *Leaf, expansions []string) {
+	if q == nil {
+		q = new(query)
+	}
+	q.literal = true
+	leaf, expansions = n.find(elements, nil, q)
+	q.literal = false
+	return leaf, expansions
+}
+
+// Finds the leaf of a literal edge matching el, the last element of the path.
+func (n *Node) findLiteral(el string, exp []string, q *query) (leaf *Leaf, expansions []string) {
+	for _, edge := range n.sorted {
+		if len(edge.wildcards) > 0 || edge.literals != nil {
+			continue
+		}
+		if _, ok := edge.match(el, n.conf, false); !ok {
+			continue
+		}
+		if found := q.resolve(edge.node.leaf); found != nil && (leaf == nil || n.conf.ranks(found, leaf)) {
+			leaf, expansions = found, exp
+		}
+	}
+	return leaf, expansions
+}
+
+// Splits a path element into the part before its extension and the extension,
+// the part after the last dot unless that's the first character.
+func cutExtension(el string) (base, ext string) {
+	dot := strings.LastIndexByte(el, '.')
+	if dot <= 0 || dot == len(el)-1 {
+		return el, ""
+	}
+	return el[:dot], el[dot+1:]
+}
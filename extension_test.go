@@ -0,0 +1,63 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractExtension(t *testing.T) {
+	n := New(WithExtractExtension())
+	l1, _ := n.Add("/users/:id", 1)
+	n.Add("/files/*path", 2)
+	n.Add("/robots.txt", 3)
+	n.Add("/", 4)
+
+	cases := []struct {
+		path       string
+		value      interface{}
+		expansions []string
+	}{
+		{"/users/7.json", 1, []string{"7", "json"}},
+		{"/users/7", 1, []string{"7", ""}},
+		{"/users/archive.tar.gz", 1, []string{"archive.tar", "gz"}},
+		{"/users/.hidden", 1, []string{".hidden", ""}},
+		{"/users/7.", 1, []string{"7.", ""}},
+		{"/users/7.json/", 1, []string{"7.json", ""}},
+		{"/files/a/b.html", 2, []string{"a/b", "html"}},
+		{"/robots.txt", 3, []string{""}},
+		{"/", 4, []string{""}},
+	}
+	for _, c := range cases {
+		leaf, exp := n.Find(c.path)
+		if leaf == nil || leaf.Value != c.value || !reflect.DeepEqual(exp, c.expansions) {
+			t.Errorf("%s: (actual) %v %v != %v %v (expected)", c.path, leaf, exp, c.value, c.expansions)
+		}
+	}
+
+	if names := l1.WildcardNames(); !reflect.DeepEqual(names, []string{"id", "ext"}) {
+		t.Errorf("Wildcard names (actual) %v", names)
+	}
+	m := n.Match("/files/a/b.html")
+	if m == nil || !reflect.DeepEqual(m.Params(), map[string]string{"path": "a/b", "ext": "html"}) || m.Star() != "a/b" {
+		t.Errorf("Match (actual) %v", m)
+	}
+
+	reverse(t, n, l1, map[string]string{"id": "7", "ext": "json"}, "/users/7.json", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{"id": "7"}, "/users/7", map[string]string{}, nil)
+
+	// A literal with the extension wins over a wildcard added before it
+	n = New(WithExtractExtension())
+	n.Add("/:page", 1)
+	n.Add("/robots.txt", 2)
+	n.Add("/docs/:page", 3)
+	n.Add("/docs/:[1,8]file;.txt", 4)
+	for path, expected := range map[string][]string{"/robots.txt": {""}, "/index.html": {"index", "html"}, "/docs/a.txt": {"a", "txt"}} {
+		leaf, exp := n.Find(path)
+		if leaf == nil || !reflect.DeepEqual(exp, expected) {
+			t.Errorf("%s: (actual) %v %v != %v (expected)", path, leaf, exp, expected)
+		}
+	}
+	if leaf, _ := n.Find("/robots.txt"); leaf == nil || leaf.Value != 2 {
+		t.Errorf("/robots.txt: (actual) %v", leaf)
+	}
+}
@@ -0,0 +1,58 @@
+package pathtree
+
+// SetConstraintFailure makes Find return a leaf with the value val for paths
+// matching the pattern of this leaf except for the constraints of its
+// wildcards, eg. to reply 400 rather than 404. The returned leaf has the same
+// pattern and wildcards, and Find returns the expansions of its wildcards
+// which Violation tells the first violating one of.
+//
+// Constraint failures are only considered when no pattern matches the path,
+// so that fallback patterns like stars win over them, whatever their order.
+// Among the patterns a path violates, the one added first is used.
+func (l *Leaf) SetConstraintFailure(val interface{}) *Leaf {
+	l.failure = &Leaf{
+		Value:     val,
+		Wildcards: l.Wildcards,
+		order:     l.order,
+		parent:    l.parent,
+		slashend:  l.slashend,
+		pattern:   l.pattern,
+		source:    l.source,
+		failed:    l,
+	}
+	l.root().conf.failures = true
+	return l.failure
+}
+
+// ConstraintFailure returns the leaf whose constraint failures this leaf was
+// set for with SetConstraintFailure, or nil.
+func (l *Leaf) ConstraintFailure() *Leaf {
+	return l.failed
+}
+
+// Violation returns the first wildcard of the leaf whose expansion doesn't
+// satisfy its constraints, and the expansion.
+func (l *Leaf) Violation(expansions []string) (wildcard Wildcard, value string, ok bool) {
+	length := func(s string) int { return len(s) }
+	if l.parent != nil {
+		length = l.parent.conf.length
+	}
+	for i, value := range expansions {
+		if i < len(l.Wildcards) && !l.Wildcards[i].acceptsLength(value, length(value)) {
+			return l.Wildcards[i], value, true
+		}
+	}
+	return Wildcard{}, "", false
+}
+
+// Finds the constraint failure leaf of the first pattern the elements match
+// ignoring the constraints of wildcards.
+func (n *Node) findFailure(elements []string, slashend bool) (leaf *Leaf, expansions []string) {
+	q := &query{strict: n.conf.strict, slashend: slashend, relaxed: true, pick: func(l *Leaf) *Leaf {
+		if l.hidden {
+			return nil
+		}
+		return l.failure
+	}}
+	return n.find(elements, nil, q)
+}
@@ -0,0 +1,48 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConstraintFailure(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/users/:[2,4]id/:kind(a|b)", 1)
+	f1 := l1.SetConstraintFailure(2)
+	n.Add("/users/:[5,5]id/x", 3)
+
+	found(t, n, "/users/abc/a", []string{"abc", "a"}, 1)
+	found(t, n, "/users/abcde/x", []string{"abcde"}, 3)
+	notfound(t, n, "/users/abc")
+
+	cases := []struct {
+		path      string
+		exp       []string
+		violation Wildcard
+		value     string
+	}{
+		{"/users/a/a", []string{"a", "a"}, Wildcard{Name: "id", Min: 2, Max: 4}, "a"},
+		{"/users/abc/c", []string{"abc", "c"}, Wildcard{Name: "kind", Enum: []string{"a", "b"}}, "c"},
+		{"/users/abcde/a", []string{"abcde", "a"}, Wildcard{Name: "id", Min: 2, Max: 4}, "abcde"},
+	}
+	for _, c := range cases {
+		leaf, exp := n.Find(c.path)
+		if leaf != f1 || !reflect.DeepEqual(exp, c.exp) {
+			t.Errorf("%s: (actual) %v %v != %v %v (expected)", c.path, leaf, exp, f1, c.exp)
+			continue
+		}
+		if leaf.ConstraintFailure() != l1 || leaf.Pattern() != l1.Pattern() {
+			t.Errorf("%s: ConstraintFailure (actual) %v", c.path, leaf.ConstraintFailure())
+		}
+		if w, value, ok := leaf.Violation(exp); !ok || !reflect.DeepEqual(w, c.violation) || value != c.value {
+			t.Errorf("%s: Violation (actual) %v %q %v", c.path, w, value, ok)
+		}
+	}
+
+	n.Add("/*path", 4)
+	found(t, n, "/users/a/a", []string{"users/a/a"}, 4)
+
+	if c := n.Clone(); c.Get("/users/:[2,4]id/:kind(a|b)").failure.ConstraintFailure() == l1 {
+		t.Errorf("Clone shares the constraint failure leaf")
+	}
+}
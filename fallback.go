@@ -0,0 +1,58 @@
+package pathtree
+
+import (
+	"errors"
+	"strings"
+)
+
+// SetFallback makes Find return a leaf with the value val for paths below the
+// literal prefix that no pattern matches, eg. to reply with a JSON error under
+// "/api" and an HTML page elsewhere. The fallback of the longest prefix of the
+// path is used. Unlike a star, a fallback never takes priority over a pattern,
+// whatever their order. Setting the fallback of a prefix again replaces it.
+func (n *Node) SetFallback(prefix string, val interface{}) error {
+	elements, _, ok := n.lookup(prefix)
+	if !ok {
+		return errors.New("Path must begin with " + n.conf.sep)
+	}
+	for _, el := range elements {
+		if (len(el) > 0 && el[0] == '*') || len(newSegment(el).wildcards) > 0 {
+			return errors.New("fallback prefix not literal")
+		}
+	}
+
+	if n.conf.fallbacks == nil {
+		n.conf.fallbacks = make(map[string]*Leaf)
+	}
+	n.conf.fallbacks[n.conf.fallbackKey(elements)] = &Leaf{
+		Value:    val,
+		parent:   n,
+		pattern:  prefix,
+		fallback: true,
+	}
+	return nil
+}
+
+// IsFallback reports if the leaf was set with SetFallback rather than added.
+func (l *Leaf) IsFallback() bool {
+	return l.fallback
+}
+
+// Returns the fallback leaf of the longest prefix of elements, or nil.
+func (n *Node) findFallback(elements []string) *Leaf {
+	for i := len(elements); i >= 0; i-- {
+		if leaf, ok := n.conf.fallbacks[n.conf.fallbackKey(elements[:i])]; ok {
+			return leaf
+		}
+	}
+	return nil
+}
+
+// Returns the key of the fallback for a prefix split into elements.
+func (c *config) fallbackKey(elements []string) string {
+	key := strings.Join(elements, c.sep)
+	if c.fold {
+		key = strings.ToLower(key)
+	}
+	return key
+}
@@ -0,0 +1,50 @@
+package pathtree
+
+import "testing"
+
+func TestFallback(t *testing.T) {
+	n := New(WithCaseInsensitive())
+	n.Add("/api/users/:id", "user")
+	n.Add("/api/*rest", "rest")
+	n.Add("/docs/:page", "page")
+	if err := n.SetFallback("/", "html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.SetFallback("/docs/v1", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.SetFallback("/api/:version", "x"); err == nil {
+		t.Error("SetFallback should reject wildcards")
+	}
+	if err := n.SetFallback("api", "x"); err == nil {
+		t.Error("SetFallback should reject relative prefixes")
+	}
+
+	for _, c := range []struct {
+		path     string
+		value    interface{}
+		fallback bool
+	}{
+		{"/api/users/1", "user", false},
+		{"/api/anything", "rest", false},
+		{"/docs/intro", "page", false},
+		{"/docs/v1/a/b", "v1", true},
+		{"/DOCS/V1/a/b", "v1", true},
+		{"/docs/v2/a", "html", true},
+		{"/", "html", true},
+	} {
+		leaf, exp := n.Find(c.path)
+		if leaf == nil || leaf.Value != c.value || leaf.IsFallback() != c.fallback {
+			t.Errorf("Find(%s) (actual) %v %v", c.path, leaf, exp)
+		} else if c.fallback && exp != nil {
+			t.Errorf("Find(%s) fallback should have no expansions (actual) %v", c.path, exp)
+		}
+	}
+
+	n.SetFallback("/", "page")
+	c := n.Clone()
+	n.SetFallback("/", "other")
+	if leaf, _ := c.Find("/x/y"); leaf == nil || leaf.Value != "page" {
+		t.Errorf("Clone fallback (actual) %v", leaf)
+	}
+}
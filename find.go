@@ -0,0 +1,482 @@
+package pathtree
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// FindElements finds a path that has already been split into its elements,
+// without a leading empty element, like Find does with a key.
+func (n *Node) FindElements(elements []string) (leaf *Leaf, expansions []string) {
+	return n.find(elements, nil, nil)
+}
+
+// FindAppend finds a given path like Find, appending the expansions to dst
+// and returning the extended slice, so callers can keep the expansions of
+// several paths in one slice.
+func (n *Node) FindAppend(dst []string, key string) (leaf *Leaf, expansions []string) {
+	leaf, expansions = n.Find(key)
+	return leaf, append(dst, expansions...)
+}
+
+// FindContext finds a given path like Find, stopping with the error of ctx
+// once it's done. The context is checked every few nodes visited.
+func (n *Node) FindContext(ctx context.Context, key string) (leaf *Leaf, expansions []string, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, ctx: ctx}
+	leaf, expansions = n.findPath(elements, slashend, q)
+	return leaf, expansions, q.err
+}
+
+// MatchStatus tells a path no pattern matches from one whose leafs were all
+// rejected.
+type MatchStatus int
+
+const (
+	NoMatch  MatchStatus = iota // no pattern matches the path
+	Matched                     // a leaf was found
+	Rejected                    // patterns match the path but their leafs were rejected
+)
+
+func (s MatchStatus) String() string {
+	switch s {
+	case Matched:
+		return "matched"
+	case Rejected:
+		return "rejected"
+	}
+	return "no match"
+}
+
+// FindStatus finds a given path like Find, also returning the leafs of the
+// patterns matching the path that were rejected as their condition or the
+// leaf they're an alias of is disabled, or they have expired. The status is
+// Rejected if there are such leafs but no other was found.
+func (n *Node) FindStatus(key string) (leaf *Leaf, expansions []string, status MatchStatus, rejected []*Leaf) {
+	return n.findStatus(key, nil)
+}
+
+// Finds a given path like FindStatus, using the leafs pick returns if set.
+func (n *Node) findStatus(key string, pick func(*Leaf) *Leaf) (leaf *Leaf, expansions []string, status MatchStatus, rejected []*Leaf) {
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil, nil, NoMatch, nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, reject: true, pick: pick}
+	leaf, expansions = n.findPath(elements, slashend, q)
+	switch {
+	case leaf != nil:
+		return leaf, expansions, Matched, q.rejected
+	case len(q.rejected) > 0:
+		return nil, nil, Rejected, q.rejected
+	}
+	return nil, nil, NoMatch, nil
+}
+
+// FindMethodStatus finds a given path like FindMethod and reports its status
+// like FindStatus. Leafs matching the path without a value for method are
+// rejected, and the lookup goes on to those of lower priority, so the status
+// is Rejected only if no leaf matching the path has one.
+func (n *Node) FindMethodStatus(method, key string) (leaf *Leaf, expansions []string, status MatchStatus, rejected []*Leaf) {
+	return n.findStatus(key, func(l *Leaf) *Leaf {
+		target := l
+		if l.alias != nil {
+			target = l.alias
+		}
+		if _, ok := target.methods[method]; !ok || l.hidden {
+			return nil
+		}
+		return l
+	})
+}
+
+// FindHostPath finds a path on a host, eg. from the Host header and URL of an
+// HTTP request, as the path with the host as its first element. Host patterns
+// are added that way, with wildcards as in any element:
+//
+//	n.Add("/example.com/users/:id", users)
+//	n.Add("/:tenant;.example.com/users/:id", tenantUsers)
+//	n.FindHostPath("acme.example.com:8080", "/users/7")
+//
+// The port of host is ignored and the host is lowercased.
+func (n *Node) FindHostPath(host, path string) (leaf *Leaf, expansions []string) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" || strings.Contains(host, n.conf.sep) || !strings.HasPrefix(path, n.conf.sep) {
+		return nil, nil
+	}
+	return n.Find(n.conf.sep + strings.ToLower(host) + path)
+}
+
+// FindFirst finds the keys in turn like Find, returning the first leaf found
+// along with the key it was found for.
+func (n *Node) FindFirst(keys ...string) (leaf *Leaf, expansions []string, key string) {
+	for _, key = range keys {
+		if leaf, expansions = n.Find(key); leaf != nil {
+			return leaf, expansions, key
+		}
+	}
+	return nil, nil, ""
+}
+
+// SegmentMatch pairs an element of a pattern with the input it matched.
+type SegmentMatch struct {
+	Pattern    string // the pattern element, eg. ":id" or "*path"
+	Input      string // the input element(s) it matched
+	IsWildcard bool   // if the pattern element contains wildcards
+}
+
+// FindAligned finds a given path like Find, and returns for every element of
+// the matched pattern the input it matched, literals included. A star matches
+// all remaining input elements, joined with '/'.
+func (n *Node) FindAligned(key string) (leaf *Leaf, segments []SegmentMatch) {
+	elements, _, ok := n.lookup(key)
+	if !ok {
+		return nil, nil
+	}
+
+	leaf, expansions := n.find(elements, nil, nil)
+	if leaf == nil {
+		return nil, nil
+	}
+
+	segments = make([]SegmentMatch, 0, len(elements)+1)
+	pos, wildcard := 0, 0
+	for _, edge := range leaf.edges() {
+		if edge.maxspan > 0 {
+			count := strings.Count(expansions[wildcard], n.conf.sep) + 1
+			segments = append(segments, SegmentMatch{edge.repr, strings.Join(elements[pos:pos+count], n.conf.sep), true})
+			pos += count
+			wildcard++
+			continue
+		}
+		if edge.literals != nil {
+			for _, literal := range edge.literals {
+				segments = append(segments, SegmentMatch{literal, elements[pos], false})
+				pos++
+			}
+			continue
+		}
+		segments = append(segments, SegmentMatch{edge.repr, elements[pos], len(edge.wildcards) > 0})
+		pos++
+		wildcard += len(edge.wildcards)
+	}
+	if leaf.isStar() {
+		star := leaf.Wildcards[len(leaf.Wildcards)-1]
+		segments = append(segments, SegmentMatch{starElement(star.Name), strings.Join(elements[pos:], n.conf.sep), true})
+	}
+	return leaf, segments
+}
+
+// Span is the byte range of a wildcard expansion in the path looked up.
+type Span struct {
+	Start, End int
+}
+
+// Of returns the expansion in key, the path looked up.
+func (s Span) Of(key string) string {
+	return key[s.Start:s.End]
+}
+
+// FindSpans finds a given path like Match, returning the byte ranges of the
+// expansions in key instead, so none have to be copied. The range of a star or
+// multi-element wildcard covers the path elements it matched, separators
+// included. With WithCollapseSlashes they index the path with its separators
+// collapsed.
+func (n *Node) FindSpans(key string) (leaf *Leaf, spans []Span) {
+	m := n.Match(key)
+	if m == nil {
+		return nil, nil
+	}
+	key = m.key
+	rel, _ := n.relative(key)
+	elements, _ := splitPath(rel, n.conf.sep)
+	offsets := make([]int, len(elements))
+	offset := len(key) - len(rel) + len(n.conf.sep)
+	for i, el := range elements {
+		offsets[i] = offset
+		offset += len(el) + len(n.conf.sep)
+	}
+	var ext string
+	if n.conf.ext {
+		ext = m.raw[len(m.raw)-1]
+		if ext != "" && !m.Leaf.isStar() {
+			last := len(elements) - 1
+			elements[last] = elements[last][:len(elements[last])-len(ext)-1]
+		}
+	}
+
+	spans = make([]Span, 0, len(m.Expansions))
+	pos := 0
+	for _, edge := range m.Leaf.edges() {
+		if pos >= len(elements) || len(spans) >= len(m.Expansions) {
+			break
+		}
+		switch {
+		case edge.maxspan > 0:
+			count := strings.Count(m.raw[len(spans)], n.conf.sep) + 1
+			last := min(pos+count, len(elements)) - 1
+			spans = append(spans, Span{offsets[pos], offsets[last] + len(elements[last])})
+			pos += count
+		case edge.literals != nil:
+			pos += len(edge.literals)
+		default:
+			start := len(spans)
+			if found, ok := edge.bounds(elements[pos], n.conf, m.Leaf.failed != nil, spans); ok {
+				spans = found
+			}
+			for i := start; i < len(spans); i++ {
+				spans[i].Start += offsets[pos]
+				spans[i].End += offsets[pos]
+			}
+			pos++
+		}
+	}
+	if m.start >= 0 {
+		spans = append(spans, Span{m.start, m.end})
+	}
+	if n.conf.ext {
+		spans = append(spans, Span{len(key) - len(ext), len(key)})
+	}
+	return m.Leaf, spans
+}
+
+// SegmentTrace shows how an element of a pattern matched its input.
+type SegmentTrace struct {
+	Pattern  string   // the pattern element, eg. "is:id;really" or "*path"
+	Input    string   // the input element(s) it matched
+	Paddings []string // the input matched by the padding around the wildcards, in order
+	Vars     []string // the wildcard expansions
+}
+
+// FindVerbose finds a given path like FindAligned, and returns for every element
+// of the matched pattern the input matched by its padding and wildcards, eg. to
+// see why "/isreally/found/now" expands "" for "/is:id;really/found/now". Stars
+// and multi-element wildcards have a single expansion and no padding.
+func (n *Node) FindVerbose(key string) (leaf *Leaf, trace []SegmentTrace) {
+	leaf, segments := n.FindAligned(key)
+	if leaf == nil {
+		return nil, nil
+	}
+
+	trace = make([]SegmentTrace, 0, len(segments))
+	for _, edge := range leaf.edges() {
+		if len(trace) == len(segments) {
+			break
+		}
+		if edge.maxspan > 0 {
+			input := segments[len(trace)].Input
+			trace = append(trace, SegmentTrace{edge.repr, input, nil, []string{input}})
+			continue
+		}
+		if edge.literals != nil {
+			for _, literal := range edge.literals {
+				trace = append(trace, SegmentTrace{literal, segments[len(trace)].Input, []string{segments[len(trace)].Input}, nil})
+			}
+			continue
+		}
+		trace = append(trace, edge.trace(segments[len(trace)].Input, n.conf, leaf.failed != nil))
+	}
+	if leaf.isStar() {
+		star := segments[len(segments)-1]
+		trace = append(trace, SegmentTrace{star.Pattern, star.Input, nil, []string{star.Input}})
+	}
+	return leaf, trace
+}
+
+// Matches an input element against the edge, keeping the padding around the
+// wildcards.
+func (e *Edge) trace(input string, c *config, relaxed bool) SegmentTrace {
+	t := SegmentTrace{Pattern: e.repr, Input: input}
+	spans, _ := e.bounds(input, c, relaxed, nil)
+	offset := 0
+	for _, span := range spans {
+		t.Paddings = append(t.Paddings, input[offset:span.Start])
+		t.Vars = append(t.Vars, input[span.Start:span.End])
+		offset = span.End
+	}
+	if !e.wildend {
+		t.Paddings = append(t.Paddings, input[offset:])
+	}
+	return t
+}
+
+// MatchResult is the result of a successful lookup.
+type MatchResult struct {
+	Leaf       *Leaf    // the leaf found
+	Expansions []string // the wildcard expansions, in order of Leaf.Wildcards
+
+	key        string   // the path looked up
+	raw        []string // the expansions before the transforms of the leaf
+	start, end int      // the byte range of the star expansion in key, -1 if none
+}
+
+// Match finds a given path like Find, returning nil if nothing was found. Star
+// expansions are sliced from key rather than joined from its elements, so long
+// ones don't have to be copied.
+func (n *Node) Match(key string) *MatchResult {
+	key = n.conf.collapsed(key)
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, path: key}
+	leaf, expansions := n.findPath(elements, slashend, q)
+	if leaf == nil {
+		return nil
+	}
+	m := &MatchResult{Leaf: leaf, Expansions: expansions, key: key, raw: q.raw, start: -1, end: -1}
+	if leaf.isStar() {
+		m.end = len(key)
+		if slashend && !n.conf.starslash {
+			m.end -= len(n.conf.sep)
+		}
+		if ext := expansions[len(expansions)-1]; n.conf.ext && ext != "" {
+			m.end -= len(ext) + 1
+		}
+		m.start = m.end - q.starlen
+	}
+	return m
+}
+
+// StarSpan returns the byte offset and length of the star expansion in the
+// path looked up, or -1 and 0 if the leaf found isn't a star. With
+// WithCollapseSlashes it's the path with its separators collapsed.
+func (m *MatchResult) StarSpan() (offset, length int) {
+	if m.start < 0 {
+		return -1, 0
+	}
+	return m.start, m.end - m.start
+}
+
+// Star returns the star expansion as it appears in the path looked up, before
+// any transform, or "" if the leaf found isn't a star.
+func (m *MatchResult) Star() string {
+	if m.start < 0 {
+		return ""
+	}
+	return m.key[m.start:m.end]
+}
+
+// Get returns the expansion of the first wildcard with the given name.
+func (m *MatchResult) Get(name string) (value string, ok bool) {
+	for i, wildcard := range m.Leaf.WildcardNames() {
+		if wildcard == name && i < len(m.Expansions) {
+			return m.Expansions[i], true
+		}
+	}
+	return "", false
+}
+
+// Params returns the expansions by wildcard name. If a name is used more than
+// once the first expansion is used.
+func (m *MatchResult) Params() map[string]string {
+	params := make(map[string]string, len(m.Expansions))
+	names := m.Leaf.WildcardNames()
+	for i := len(m.Expansions) - 1; i >= 0; i-- {
+		params[names[i]] = m.Expansions[i]
+	}
+	return params
+}
+
+// FindDetailed finds a given path like Find, also reporting how far the lookup
+// got when nothing was found. failDepth is then the index of the path element,
+// after the base path, that no edge matched on the branch that got deepest,
+// or the number of elements if a branch matched them all but has no leaf, and
+// lastNode is the node that branch ended at. failDepth is -1 on success, and
+// lastNode nil if the path isn't below the base path.
+func (n *Node) FindDetailed(key string) (leaf *Leaf, expansions []string, failDepth int, lastNode *Node) {
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil, nil, 0, nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, trace: true}
+	if leaf, expansions = n.find(elements, nil, q); leaf == nil && n.conf.failures {
+		leaf, expansions = n.findFailure(elements, slashend)
+	}
+	if leaf != nil {
+		return leaf, leaf.transform(expansions), -1, leaf.parent
+	}
+	return nil, nil, len(elements) - q.left, q.deepest
+}
+
+// Capture is the expansion of a wildcard.
+type Capture struct {
+	WildcardIndex int    // the index of the wildcard in Leaf.Wildcards
+	Name          string // the name of the wildcard
+	Value         string // the expansion
+}
+
+// FindIndexed finds a given path like Find, pairing each expansion with its
+// wildcard, which unlike MatchResult.Params works for repeated names.
+func (n *Node) FindIndexed(key string) (leaf *Leaf, captures []Capture) {
+	leaf, expansions := n.Find(key)
+	if leaf == nil {
+		return nil, nil
+	}
+	captures = make([]Capture, len(expansions))
+	names := leaf.WildcardNames()
+	for i, value := range expansions {
+		captures[i] = Capture{i, names[i], value}
+	}
+	return leaf, captures
+}
+
+// FindPattern finds a given path like Find, also returning the pattern of the
+// leaf found, which is recorded when adding rather than rebuilt from the tree.
+func (n *Node) FindPattern(key string) (pattern string, leaf *Leaf, expansions []string, ok bool) {
+	if leaf, expansions = n.Find(key); leaf == nil {
+		return "", nil, nil, false
+	}
+	return leaf.Pattern(), leaf, expansions, true
+}
+
+// FindSuffix finds the path elements left over by a lookup in another tree,
+// eg. the tree of a backend group chosen by a coarser tree, without joining
+// and splitting them again. The elements must already be split on the
+// separator of this tree and not contain it, and the base of the tree isn't
+// stripped from them. The expansions start with a copy of inherited, followed
+// by those of the leaf found.
+func (n *Node) FindSuffix(elements []string, slashend bool, inherited []string) (leaf *Leaf, expansions []string) {
+	var q *query
+	if n.conf.strict || n.conf.starslash {
+		q = &query{strict: n.conf.strict, slashend: slashend}
+	}
+	if leaf, expansions = n.findPath(elements, slashend, q); leaf == nil {
+		return nil, nil
+	}
+	return leaf, append(append(make([]string, 0, len(inherited)+len(expansions)), inherited...), expansions...)
+}
+
+// FindNearest finds a given path like Find or, if nothing matches it, drops its
+// last elements one at a time until something does, eg. to show the closest
+// parent route of a path not found. dropped is the number of elements dropped,
+// 0 if the path itself matched. Returns nil if not even "/" matches.
+func (n *Node) FindNearest(key string) (leaf *Leaf, expansions []string, dropped int) {
+	sep := n.conf.sep
+	for {
+		if leaf, expansions = n.Find(key); leaf != nil {
+			return leaf, expansions, dropped
+		}
+		i := strings.LastIndex(strings.TrimSuffix(key, sep), sep)
+		if i < 0 || key == sep {
+			return nil, nil, 0
+		}
+		if key = key[:i]; key == "" {
+			key = sep
+		}
+		dropped++
+	}
+}
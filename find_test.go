@@ -0,0 +1,412 @@
+package pathtree
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindAligned(t *testing.T) {
+	n := New()
+
+	n.Add("/", 1)
+	n.Add("/path/:i/nowhere", 2)
+	n.Add("/files/*path", 3)
+
+	leaf, segments := n.FindAligned("/path/to/nowhere/")
+	expected := []SegmentMatch{{"path", "path", false}, {":i", "to", true}, {"nowhere", "nowhere", false}}
+	if leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(segments, expected) {
+		t.Errorf("Segments (actual) %v != %v (expected)", segments, expected)
+	}
+
+	leaf, segments = n.FindAligned("/files/a/b")
+	expected = []SegmentMatch{{"files", "files", false}, {"*path", "a/b", true}}
+	if leaf == nil || leaf.Value != 3 || !reflect.DeepEqual(segments, expected) {
+		t.Errorf("Segments (actual) %v != %v (expected)", segments, expected)
+	}
+
+	leaf, segments = n.FindAligned("/")
+	if leaf == nil || leaf.Value != 1 || len(segments) != 0 {
+		t.Errorf("Segments (actual) %v != [] (expected)", segments)
+	}
+
+	if leaf, _ := n.FindAligned("/missing"); leaf != nil {
+		t.Errorf("Should not have found: /missing")
+	}
+}
+
+func TestFindDetailed(t *testing.T) {
+	n := New()
+	n.Add("/api/v2/users/:id", 1)
+	n.Add("/api/v2/:kind;s/:id/edit", 2)
+	n.Add("/static/*path", 3)
+
+	leaf, exp, depth, last := n.FindDetailed("/api/v2/users/7")
+	if leaf == nil || leaf.Value != 1 || !reflect.DeepEqual(exp, []string{"7"}) || depth != -1 || last != leaf.parent {
+		t.Errorf("Success (actual) %v %v %d %v", leaf, exp, depth, last)
+	}
+
+	cases := []struct {
+		path    string
+		depth   int
+		pattern string
+	}{
+		{"/api/v2/abc/7", 2, "/api/v2"},
+		{"/api/v2/posts/7/view", 4, "/api/v2/:kind;s/:id"},
+		{"/api/v2/posts/7", 4, "/api/v2/:kind;s/:id"},
+		{"/unknown", 0, ""},
+		{"/api/v3", 1, "/api"},
+	}
+	for _, c := range cases {
+		leaf, _, depth, last := n.FindDetailed(c.path)
+		if leaf != nil || depth != c.depth || last == nil {
+			t.Errorf("%s: (actual) %v %d %v", c.path, leaf, depth, last)
+			continue
+		}
+		var pattern string
+		for _, edge := range (&Leaf{parent: last}).edges() {
+			pattern += "/" + edge.repr
+		}
+		if pattern != c.pattern {
+			t.Errorf("%s: last node (actual) %q != %q (expected)", c.path, pattern, c.pattern)
+		}
+	}
+}
+
+func TestFindIndexed(t *testing.T) {
+	n := New()
+	n.Add("/:a/:b/:a", 1)
+	n.Add("/", 2)
+
+	leaf, captures := n.FindIndexed("/x/y/z")
+	expected := []Capture{{0, "a", "x"}, {1, "b", "y"}, {2, "a", "z"}}
+	if leaf == nil || !reflect.DeepEqual(captures, expected) {
+		t.Errorf("Captures (actual) %v != %v (expected)", captures, expected)
+	}
+	if leaf, captures := n.FindIndexed("/"); leaf == nil || len(captures) != 0 {
+		t.Errorf("Captures of the root (actual) %v", captures)
+	}
+	if leaf, _ := n.FindIndexed("/x"); leaf != nil {
+		t.Errorf("Should not have found: /x")
+	}
+}
+
+func TestFindPattern(t *testing.T) {
+	n := New()
+	n.Add("/users/:id/", 1)
+	n.Add("/files/*path", 2)
+	n.Add("/a/b/c", 3)
+	n.Compact()
+
+	for key, expected := range map[string]string{"/users/7": "/users/:id/", "/files/a/b": "/files/*path", "/a/b/c": "/a/b/c"} {
+		pattern, leaf, _, ok := n.FindPattern(key)
+		if !ok || pattern != expected || pattern != leaf.buildPattern(leaf.isStar()) {
+			t.Errorf("%s: (actual) %q %v != %q (expected)", key, pattern, ok, expected)
+		}
+	}
+	if pattern, leaf, exp, ok := n.FindPattern("/missing"); ok || pattern != "" || leaf != nil || exp != nil {
+		t.Errorf("Should not have found: /missing")
+	}
+}
+
+func TestFindFirst(t *testing.T) {
+	n := New()
+
+	n.Add("/en/docs/:page", 1)
+	n.Add("/docs/:page", 2)
+
+	leaf, exp, key := n.FindFirst("/de/docs/intro", "/docs/intro", "/en/docs/intro")
+	if leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(exp, []string{"intro"}) || key != "/docs/intro" {
+		t.Errorf("FindFirst (actual) %v %v %s", leaf, exp, key)
+	}
+
+	if leaf, exp, key := n.FindFirst("/de/docs/intro", "/fr/docs/intro"); leaf != nil || exp != nil || key != "" {
+		t.Errorf("FindFirst should not have found anything (actual) %v %v %s", leaf, exp, key)
+	}
+	if leaf, _, _ := n.FindFirst(); leaf != nil {
+		t.Errorf("FindFirst without keys should not have found anything")
+	}
+}
+
+func TestFindContext(t *testing.T) {
+	n := New()
+	for i := 0; i < 200; i++ {
+		n.Add(fmt.Sprintf("/:a/:b%d/:c/y", i), i)
+	}
+	n.Add("/:a/:b/:c/z", "z")
+
+	leaf, exp, err := n.FindContext(context.Background(), "/x1/b/c/z")
+	if err != nil || leaf == nil || leaf.Value != "z" || !reflect.DeepEqual(exp, []string{"x1", "b", "c"}) {
+		t.Errorf("FindContext (actual) %v %v %v", leaf, exp, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if leaf, exp, err := n.FindContext(ctx, "/x1/b/c/z"); err != context.Canceled || leaf != nil || exp != nil {
+		t.Errorf("FindContext cancelled (actual) %v %v %v", leaf, exp, err)
+	}
+
+	// Cancelled during the lookup
+	ctx, cancel = context.WithCancel(context.Background())
+	q := &query{ctx: ctx}
+	q.cancelled()
+	cancel()
+	if leaf, _ := n.findPath([]string{"x1", "b", "c", "z"}, false, q); leaf != nil || q.err != context.Canceled {
+		t.Errorf("find cancelled (actual) %v %v", leaf, q.err)
+	}
+}
+
+func TestMatchStarSpan(t *testing.T) {
+	n := New()
+	n.Add("/objects/:bucket/*key", 1)
+	n.Add("/users/:id", 2)
+
+	key := "/objects/b/" + strings.Repeat("abc/", 1000) + "end/"
+	m := n.Match(key)
+	if m == nil || m.Leaf.Value != 1 {
+		t.Fatalf("Should have found: %s", key)
+	}
+	offset, length := m.StarSpan()
+	expected := strings.Repeat("abc/", 1000) + "end"
+	if offset != 11 || length != len(expected) || m.Star() != expected || m.Expansions[1] != expected {
+		t.Errorf("Star span (actual) %d %d %q", offset, length, m.Star())
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { n.Match(key) }); allocs > 5 {
+		t.Errorf("Match allocated %v times", allocs)
+	}
+
+	if m := n.Match("/users/7"); m == nil || m.Star() != "" {
+		t.Errorf("Star of a non star match (actual) %v", m)
+	} else if offset, length := m.StarSpan(); offset != -1 || length != 0 {
+		t.Errorf("Star span of a non star match (actual) %d %d", offset, length)
+	}
+
+	n = New(WithStarSlash())
+	n.Add("/files/*path", 1)
+	if m := n.Match("/files/a//b/"); m == nil || m.Star() != "a//b/" || m.Expansions[0] != "a//b/" {
+		t.Errorf("Star with slash (actual) %v", m)
+	}
+}
+
+func TestFindStatus(t *testing.T) {
+	n := New()
+	active := false
+	l1, _ := n.AddIf("/beta/:page", 1, func() bool { return active })
+	l2, _ := n.AddIf("/:section/index", 2, func() bool { return active })
+	n.AddMethod("GET", "/users/:id", 3)
+
+	leaf, exp, status, rejected := n.FindStatus("/beta/index")
+	if leaf != nil || exp != nil || status != Rejected || len(rejected) != 2 || !slices.Contains(rejected, l1) || !slices.Contains(rejected, l2) {
+		t.Errorf("Rejected (actual) %v %v %v %v", leaf, exp, status, rejected)
+	}
+	if leaf, _, status, rejected := n.FindStatus("/missing"); leaf != nil || status != NoMatch || rejected != nil {
+		t.Errorf("No match (actual) %v %v %v", leaf, status, rejected)
+	}
+
+	active = true
+	if leaf, exp, status, rejected := n.FindStatus("/beta/index"); leaf != l1 || exp[0] != "index" || status != Matched || rejected != nil {
+		t.Errorf("Matched (actual) %v %v %v %v", leaf, exp, status, rejected)
+	}
+
+	if leaf, exp, status, _ := n.FindMethodStatus("GET", "/users/7"); leaf == nil || exp[0] != "7" || status != Matched {
+		t.Errorf("Method matched (actual) %v %v %v", leaf, exp, status)
+	}
+	if leaf, _, status, rejected := n.FindMethodStatus("POST", "/users/7"); leaf != nil || status != Rejected || len(rejected) != 1 || rejected[0].Value != nil {
+		t.Errorf("Method rejected (actual) %v %v %v", leaf, status, rejected)
+	}
+	if leaf, _, status, _ := n.FindMethodStatus("POST", "/x/y/z"); leaf != nil || status != NoMatch {
+		t.Errorf("Method without match (actual) %v %v", leaf, status)
+	}
+	if NoMatch.String() != "no match" || Rejected.String() != "rejected" {
+		t.Errorf("Status strings (actual) %s %s", NoMatch, Rejected)
+	}
+}
+
+func TestFindHostPath(t *testing.T) {
+	n := New()
+	n.Add("/example.com/users/:id", 1)
+	n.Add("/:tenant;.example.com/users/:id", 2)
+	n.Add("/:host/*path", 3)
+
+	cases := []struct {
+		host, path string
+		value      interface{}
+		expansions []string
+	}{
+		{"example.com", "/users/7", 1, []string{"7"}},
+		{"Example.COM:8080", "/users/7", 1, []string{"7"}},
+		{"acme.example.com", "/users/7", 2, []string{"acme", "7"}},
+		{"other.org", "/a/b", 3, []string{"other.org", "a/b"}},
+		{"[::1]:80", "/a", 3, []string{"::1", "a"}},
+	}
+	for _, c := range cases {
+		leaf, exp := n.FindHostPath(c.host, c.path)
+		if leaf == nil || leaf.Value != c.value || !reflect.DeepEqual(exp, c.expansions) {
+			t.Errorf("%s %s: (actual) %v %v != %v %v (expected)", c.host, c.path, leaf, exp, c.value, c.expansions)
+		}
+	}
+
+	for _, c := range [][2]string{{"", "/a"}, {"example.com", "users"}, {"a/b", "/c"}} {
+		if leaf, _ := n.FindHostPath(c[0], c[1]); leaf != nil {
+			t.Errorf("%s %s: Should not have found anything", c[0], c[1])
+		}
+	}
+}
+
+func TestFindSuffix(t *testing.T) {
+	groups := New()
+	groups.Add("/api/:group/*rest", 1)
+	backend := New(WithStrictSlash())
+	backend.Add("/users/:id", 2)
+	backend.Add("/files/*path", 3)
+	backend.Add("/", 4)
+
+	_, exp := groups.Find("/api/eu/users/7")
+	inherited := exp[:1:2]
+	leaf, exp := backend.FindSuffix(strings.Split(exp[1], "/"), false, inherited)
+	if leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(exp, []string{"eu", "7"}) {
+		t.Errorf("FindSuffix (actual) %v %v", leaf, exp)
+	}
+	if exp[0] = "us"; inherited[0] != "eu" {
+		t.Errorf("FindSuffix aliased the inherited expansions")
+	}
+
+	if leaf, exp := backend.FindSuffix([]string{"files", "a", "b"}, false, nil); leaf == nil || leaf.Value != 3 || !reflect.DeepEqual(exp, []string{"a/b"}) {
+		t.Errorf("FindSuffix of a star (actual) %v %v", leaf, exp)
+	}
+	if leaf, _ := backend.FindSuffix(nil, true, []string{"eu"}); leaf == nil || leaf.Value != 4 {
+		t.Errorf("FindSuffix of the root (actual) %v", leaf)
+	}
+	if leaf, exp := backend.FindSuffix([]string{"users", "7"}, true, inherited); leaf != nil || exp != nil {
+		t.Errorf("FindSuffix with a trailing slash (actual) %v %v", leaf, exp)
+	}
+}
+
+func TestFindNearest(t *testing.T) {
+	n := New()
+	n.Add("/docs/:page", 1)
+	n.Add("/docs/:page/edit", 2)
+	n.Add("/", 3)
+
+	cases := []struct {
+		path       string
+		value      interface{}
+		expansions []string
+		dropped    int
+	}{
+		{"/docs/intro/edit", 2, []string{"intro"}, 0},
+		{"/docs/intro/history", 1, []string{"intro"}, 1},
+		{"/docs/intro/edit/x/y/", 2, []string{"intro"}, 2},
+		{"/docs", 3, nil, 1},
+		{"/blog/2024/01", 3, nil, 3},
+		{"/", 3, nil, 0},
+	}
+	for _, c := range cases {
+		leaf, exp, dropped := n.FindNearest(c.path)
+		if leaf == nil || leaf.Value != c.value || !reflect.DeepEqual(exp, c.expansions) || dropped != c.dropped {
+			t.Errorf("%s: (actual) %v %v %d != %v %v %d (expected)", c.path, leaf, exp, dropped, c.value, c.expansions, c.dropped)
+		}
+	}
+
+	n = New()
+	n.Add("/docs/:page", 1)
+	if leaf, exp, dropped := n.FindNearest("/blog/2024"); leaf != nil || exp != nil || dropped != 0 {
+		t.Errorf("Should not have found anything (actual) %v %v %d", leaf, exp, dropped)
+	}
+	if leaf, _, _ := n.FindNearest("docs"); leaf != nil {
+		t.Errorf("Should not have found: docs")
+	}
+}
+
+func TestFindSpans(t *testing.T) {
+	n := New()
+	n.Add("/users/:id/posts/v:major;.:minor", 1)
+	n.Add("/files/:bucket/*path", 2)
+	n.Add("/repos/:2{repo}/blob/:ref", 3)
+	n.Add("/a/b/c", 4)
+	n.Compact()
+
+	cases := []struct {
+		path       string
+		value      interface{}
+		expansions []string
+	}{
+		{"/users/7/posts/v1.22", 1, []string{"7", "1", "22"}},
+		{"/files/b/x/y/z/", 2, []string{"b", "x/y/z"}},
+		{"/repos/me/tree/blob/main", 3, []string{"me/tree", "main"}},
+		{"/a/b/c", 4, nil},
+	}
+	for _, c := range cases {
+		leaf, spans := n.FindSpans(c.path)
+		if leaf == nil || leaf.Value != c.value || len(spans) != len(c.expansions) {
+			t.Errorf("%s: (actual) %v %v", c.path, leaf, spans)
+			continue
+		}
+		for i, span := range spans {
+			if span.Of(c.path) != c.expansions[i] {
+				t.Errorf("%s: expansion %d (actual) %q != %q (expected)", c.path, i, span.Of(c.path), c.expansions[i])
+			}
+		}
+	}
+	if leaf, spans := n.FindSpans("/missing"); leaf != nil || spans != nil {
+		t.Errorf("Should not have found: /missing")
+	}
+
+	n = New(WithExtractExtension()).WithBasePath("/api")
+	n.Add("/docs/:page", 1)
+	n.Add("/raw/*path", 2)
+	key := "/api/docs/intro.json"
+	if leaf, spans := n.FindSpans(key); leaf == nil || len(spans) != 2 || spans[0].Of(key) != "intro" || spans[1].Of(key) != "json" {
+		t.Errorf("%s: (actual) %v %v", key, leaf, spans)
+	}
+	key = "/api/raw/a/b.txt"
+	if leaf, spans := n.FindSpans(key); leaf == nil || len(spans) != 2 || spans[0].Of(key) != "a/b" || spans[1].Of(key) != "txt" {
+		t.Errorf("%s: (actual) %v %v", key, leaf, spans)
+	}
+
+	// Spans are of the path, whatever the transforms of the leaf
+	n = New()
+	l, _ := n.Add("/r/:2{repo}/x", 1)
+	l.SetTransform("repo", func(s string) string { return strings.ReplaceAll(s, "/", "-") })
+	key = "/r/a/b/x"
+	if leaf, spans := n.FindSpans(key); leaf != l || len(spans) != 1 || spans[0].Of(key) != "a/b" {
+		t.Errorf("%s: (actual) %v %v", key, leaf, spans)
+	}
+}
+
+func TestFindVerbose(t *testing.T) {
+	n := New()
+	n.Add("/path|road|street/to|through/nowhere", 1)
+	n.Add("/is:id;really/found/now|tomorrow", 2)
+	n.Add("/v:major;.:minor/*path", 3)
+
+	leaf, trace := n.FindVerbose("/isreally/found/now")
+	expected := []SegmentTrace{
+		{"is:id;really", "isreally", []string{"is", "really"}, []string{""}},
+		{"found", "found", []string{"found"}, nil},
+		{"now|tomorrow", "now", []string{"now"}, nil},
+	}
+	if leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(trace, expected) {
+		t.Errorf("Trace (actual) %v != %v (expected)", trace, expected)
+	}
+
+	leaf, trace = n.FindVerbose("/v1.2/a/b")
+	expected = []SegmentTrace{
+		{"v:major;.:minor", "v1.2", []string{"v", "."}, []string{"1", "2"}},
+		{"*path", "a/b", nil, []string{"a/b"}},
+	}
+	if leaf == nil || leaf.Value != 3 || !reflect.DeepEqual(trace, expected) {
+		t.Errorf("Trace (actual) %v != %v (expected)", trace, expected)
+	}
+
+	if leaf, trace := n.FindVerbose("/road/through/nowhere"); leaf == nil || leaf.Value != 1 || len(trace) != 3 || trace[0].Paddings[0] != "road" {
+		t.Errorf("Trace of literals (actual) %v", trace)
+	}
+	if leaf, trace := n.FindVerbose("/missing"); leaf != nil || trace != nil {
+		t.Errorf("Should not have found: /missing")
+	}
+}
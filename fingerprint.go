@@ -0,0 +1,47 @@
+package pathtree
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Fingerprint returns a hash of the patterns of the leafs below this node with
+// their wildcards and trailing slashes, but not their values, eg. to tell if
+// the routes of processes differ. Leafs are visited in the order of Walk, and
+// the order patterns were added in is left out, so trees with the same
+// patterns have the same fingerprint even if Find prefers other leafs in them.
+func (n *Node) Fingerprint() uint64 {
+	h := fnv.New64a()
+	var buf [binary.MaxVarintLen64]byte
+	write := func(s string) {
+		h.Write(binary.AppendUvarint(buf[:0], uint64(len(s))))
+		h.Write([]byte(s))
+	}
+	writeInt := func(i int) {
+		h.Write(binary.AppendVarint(buf[:0], int64(i)))
+	}
+	n.walk(func(l *Leaf) {
+		write(l.Pattern())
+		if l.slashend {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+		writeInt(len(l.Wildcards))
+		for _, w := range l.Wildcards {
+			write(w.Name)
+			writeInt(w.Min)
+			writeInt(w.Max)
+			writeInt(len(w.Enum))
+			for _, value := range w.Enum {
+				write(value)
+			}
+			if w.Numeric {
+				writeInt(1)
+			} else {
+				writeInt(0)
+			}
+		}
+	})
+	return h.Sum64()
+}
@@ -0,0 +1,48 @@
+package pathtree
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	patterns := []string{"/users/:id", "/files/*path", "/a/:[2,4]x(ab|cd)/", "/n/#id"}
+	build := func(order []int, values ...interface{}) *Node {
+		n := New()
+		for i, j := range order {
+			n.Add(patterns[j], values[i])
+		}
+		return n
+	}
+
+	n := build([]int{0, 1, 2, 3}, 1, 2, 3, 4)
+	fingerprint := n.Fingerprint()
+	if build([]int{0, 1, 2, 3}, "a", "b", "c", "d").Fingerprint() != fingerprint {
+		t.Errorf("Values changed the fingerprint")
+	}
+	if n.Clone().Fingerprint() != fingerprint {
+		t.Errorf("Clone changed the fingerprint")
+	}
+	if build([]int{1, 0, 3, 2}, 1, 2, 3, 4).Fingerprint() != fingerprint {
+		t.Errorf("The order of the patterns changed the fingerprint")
+	}
+	n.Remove("/users/:id")
+	n.Add("/users/:id", 1)
+	if n.Fingerprint() != fingerprint {
+		t.Errorf("Adding a pattern again changed the fingerprint")
+	}
+
+	others := map[string]*Node{
+		"missing":  build([]int{0, 1, 2}, 1, 2, 3),
+		"slashend": New(),
+		"bounds":   New(),
+		"numeric":  New(),
+	}
+	others["slashend"].Add("/users/:id/", 1)
+	others["bounds"].Add("/users/:[1,8]id", 1)
+	others["numeric"].Add("/users/#id", 1)
+	single := New()
+	single.Add("/users/:id", 1)
+	for name, other := range others {
+		if other.Fingerprint() == fingerprint || (name != "missing" && other.Fingerprint() == single.Fingerprint()) {
+			t.Errorf("%s: Should have changed the fingerprint", name)
+		}
+	}
+}
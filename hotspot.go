@@ -0,0 +1,65 @@
+package pathtree
+
+import "sort"
+
+// Hotspot is a node with many wildcard edges, each of which a lookup through the
+// node may have to match.
+type Hotspot struct {
+	Path  string // the patterns of the path elements up to the node, eg. "/t"
+	Edges int    // the number of wildcard edges of the node
+}
+
+// Hotspots returns the nodes below this one with more than threshold wildcard
+// edges, the ones with the most first.
+func (n *Node) Hotspots(threshold int) []Hotspot {
+	var hotspots []Hotspot
+	n.hotspots("", threshold, &hotspots)
+	sort.SliceStable(hotspots, func(i, j int) bool { return hotspots[i].Edges > hotspots[j].Edges })
+	return hotspots
+}
+
+func (n *Node) hotspots(path string, threshold int, hotspots *[]Hotspot) {
+	if count := n.wildcardEdges(); count > threshold {
+		*hotspots = append(*hotspots, Hotspot{n.conf.orRoot(path), count})
+	}
+	for _, edge := range n.sortedEdges() {
+		edge.node.hotspots(path+n.conf.sep+edge.repr, threshold, hotspots)
+	}
+}
+
+// SetOnHotspot sets a function called by Add when a node of the tree gets more
+// than threshold wildcard edges, eg. to warn about generated routes. A nil fn
+// removes it.
+func (n *Node) SetOnHotspot(threshold int, fn func(Hotspot)) {
+	n.conf.hotspot, n.conf.onhotspot = threshold, fn
+}
+
+// Calls onhotspot if an edge added to this node made it cross the threshold.
+func (n *Node) checkHotspot() {
+	if count := n.wildcardEdges(); count == n.conf.hotspot+1 {
+		var path string
+		for _, edge := range (&Leaf{parent: n}).edges() {
+			path += n.conf.sep + edge.repr
+		}
+		n.conf.onhotspot(Hotspot{n.conf.orRoot(path), count})
+	}
+}
+
+// Returns the number of edges of the node with wildcards.
+func (n *Node) wildcardEdges() int {
+	count := 0
+	for _, edge := range n.edges {
+		if len(edge.wildcards) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Returns path, or the separator for the root.
+func (c *config) orRoot(path string) string {
+	if path == "" {
+		return c.sep
+	}
+	return path
+}
@@ -0,0 +1,38 @@
+package pathtree
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHotspots(t *testing.T) {
+	n := New()
+	var warnings []Hotspot
+	n.SetOnHotspot(3, func(h Hotspot) { warnings = append(warnings, h) })
+	for i := 0; i < 5; i++ {
+		n.Add(fmt.Sprintf("/t/tenant%d_:id", i), i)
+		n.Add(fmt.Sprintf("/:[%d]x", i+1), i)
+		n.Add(fmt.Sprintf("/static/file%d", i), i)
+	}
+	n.Add("/t/:id/:a", 5)
+	n.Add("/t/:id/:b", 6)
+
+	expected := []Hotspot{{"/t", 6}, {"/", 5}}
+	if hotspots := n.Hotspots(3); !reflect.DeepEqual(hotspots, expected) {
+		t.Errorf("Hotspots (actual) %v != %v (expected)", hotspots, expected)
+	}
+	if hotspots := n.Hotspots(5); !reflect.DeepEqual(hotspots, expected[:1]) {
+		t.Errorf("Hotspots above 5 (actual) %v", hotspots)
+	}
+	expected = []Hotspot{{"/t", 4}, {"/", 4}}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Warnings (actual) %v != %v (expected)", warnings, expected)
+	}
+
+	n.SetOnHotspot(0, nil)
+	n.Add("/t/other_:id", 7)
+	if len(warnings) != 2 {
+		t.Errorf("Warnings after removing the callback (actual) %v", warnings)
+	}
+}
@@ -0,0 +1,125 @@
+// httptree routes HTTP requests by method and path using a pathtree.
+//
+// Handlers are registered per method and pattern. A pattern without a handler
+// for the method of a request doesn't hide one of lower priority with it, like
+// a star. When no pattern matching the path has one, the Mux replies 405
+// Method Not Allowed with an Allow header listing the methods registered on
+// those patterns. OPTIONS requests are answered the same way with 204 No Content
+// unless a handler was registered for them, and HEAD falls back to GET.
+//
+// Wildcard expansions are available to handlers through Request.PathValue.
+package httptree
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Jetvp/pathtree"
+)
+
+type Mux struct {
+	NotFound http.Handler // handler when no pattern matches, http.NotFound if nil
+
+	tree *pathtree.Node
+}
+
+// New returns a new Mux.
+func New() *Mux {
+	return &Mux{tree: pathtree.New()}
+}
+
+// Handle registers the handler for the given method and pattern. A pattern
+// may be registered for several methods, including with a star.
+func (m *Mux) Handle(method, pattern string, handler http.Handler) error {
+	method = strings.ToUpper(method)
+	if leaf := m.tree.Get(pattern); leaf != nil {
+		if _, ok := leaf.Method(method); ok {
+			return &DuplicateError{method, pattern}
+		}
+	}
+	_, err := m.tree.AddMethod(method, pattern, handler)
+	return err
+}
+
+// HandleFunc registers the handler function for the given method and pattern.
+func (m *Mux) HandleFunc(method, pattern string, handler func(http.ResponseWriter, *http.Request)) error {
+	return m.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+// ServeHTTP dispatches the request to the handler of the matching pattern.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.Method
+	leaf, expansions, status, rejected := m.tree.FindMethodStatus(method, r.URL.Path)
+	if leaf == nil && method == http.MethodHead {
+		method = http.MethodGet
+		leaf, expansions, status, rejected = m.tree.FindMethodStatus(method, r.URL.Path)
+	}
+
+	if leaf == nil {
+		methods := rejectedMethods(rejected)
+		if status == pathtree.NoMatch || methods == nil {
+			if m.NotFound != nil {
+				m.NotFound.ServeHTTP(w, r)
+			} else {
+				http.NotFound(w, r)
+			}
+			return
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed(methods), ", "))
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	names := leaf.WildcardNames()
+	for i, value := range expansions {
+		r.SetPathValue(names[i], value)
+	}
+	handler, _ := leaf.Method(method)
+	handler.(http.Handler).ServeHTTP(w, r)
+}
+
+// Returns the sorted methods of the leafs rejected for the method of a request.
+func rejectedMethods(rejected []*pathtree.Leaf) []string {
+	var methods []string
+	for _, leaf := range rejected {
+		for _, method := range leaf.Methods() {
+			if i := sort.SearchStrings(methods, method); i == len(methods) || methods[i] != method {
+				methods = slices.Insert(methods, i, method)
+			}
+		}
+	}
+	return methods
+}
+
+// Returns the sorted methods allowed, including implicit HEAD and OPTIONS.
+func allowed(methods []string) []string {
+	has := func(method string) bool {
+		i := sort.SearchStrings(methods, method)
+		return i < len(methods) && methods[i] == method
+	}
+	if has(http.MethodGet) && !has(http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	if !has(http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// DuplicateError is returned when a method is registered twice on a pattern.
+type DuplicateError struct {
+	Method  string
+	Pattern string
+}
+
+func (e *DuplicateError) Error() string {
+	return "duplicate method " + e.Method + " for path " + e.Pattern
+}
@@ -0,0 +1,49 @@
+package httptree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func respond(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body + r.PathValue("path") + r.PathValue("id")))
+	}
+}
+
+func TestMux(t *testing.T) {
+	m := New()
+
+	m.Handle("POST", "/users/:id", respond("create:"))
+	m.Handle("delete", "/users/:id", respond("delete:"))
+	m.Handle("GET", "/*path", respond("file:"))
+	if err := m.Handle("POST", "/users/:id", respond("again")); err == nil {
+		t.Errorf("Expected an error for a duplicate method")
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		body, allow  string
+	}{
+		{"GET", "/a/b.css", 200, "file:a/b.css", ""},
+		{"HEAD", "/a/b.css", 200, "file:a/b.css", ""},
+		{"POST", "/a/b.css", 405, "Method Not Allowed\n", "GET, HEAD, OPTIONS"},
+		{"OPTIONS", "/a/b.css", 204, "", "GET, HEAD, OPTIONS"},
+		{"POST", "/users/7", 200, "create:7", ""},
+		{"DELETE", "/users/7", 200, "delete:7", ""},
+		{"GET", "/users/7", 200, "file:users/7", ""},
+		{"PUT", "/users/7", 405, "Method Not Allowed\n", "DELETE, GET, HEAD, OPTIONS, POST"},
+		{"GET", "/", 404, "404 page not found\n", ""},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+		if w.Code != c.code || w.Body.String() != c.body || w.Header().Get("Allow") != c.allow {
+			t.Errorf("%s %s: (actual) %d %q %q != %d %q %q (expected)", c.method, c.path,
+				w.Code, w.Body.String(), w.Header().Get("Allow"), c.code, c.body, c.allow)
+		}
+	}
+}
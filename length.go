@@ -0,0 +1,45 @@
+package pathtree
+
+// Returns the length of the wildcard if the segment is a single wildcard of a
+// fixed length, like ":[8]date", or else 0.
+func (s *Segment) fixedLength() int {
+	if len(s.wildcards) != 1 || !s.wildend || len(s.padding) != 1 || len(s.padding[0]) != 1 || s.padding[0][0] != "" {
+		return 0
+	}
+	if w := s.wildcards[0]; w.Min == w.Max {
+		return w.Min
+	}
+	return 0
+}
+
+// Adds the edge to the edges of the node indexed by length, if it's a fixed
+// length wildcard.
+func (n *Node) indexLength(edge *Edge) {
+	if edge.maxspan > 0 || edge.literals != nil {
+		return
+	}
+	if length := edge.fixedLength(); length > 0 {
+		if n.lengths == nil {
+			n.lengths = make(map[int][]*Edge)
+		}
+		edge.fixed = length
+		n.lengths[length] = append(n.lengths[length], edge)
+	}
+}
+
+// Removes the edge from the edges of the node indexed by length.
+func (n *Node) unindexLength(edge *Edge) {
+	if edge.fixed == 0 {
+		return
+	}
+	edges := n.lengths[edge.fixed]
+	for i, e := range edges {
+		if e == edge {
+			n.lengths[edge.fixed] = append(edges[:i:i], edges[i+1:]...)
+			break
+		}
+	}
+	if len(n.lengths[edge.fixed]) == 0 {
+		delete(n.lengths, edge.fixed)
+	}
+}
@@ -0,0 +1,46 @@
+package pathtree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLengthIndex(t *testing.T) {
+	n := New()
+	n.Add("/:[4]year", 1)
+	n.Add("/:[8]date", 2)
+	n.Add("/:[4,8]code", 3)
+	n.Add("/:[4]other/x", 4)
+	n.Add("/*rest", 5)
+
+	if len(n.lengths[4]) != 2 || len(n.lengths[8]) != 1 {
+		t.Errorf("Length index (actual) %v", n.lengths)
+	}
+
+	found(t, n, "/2013", []string{"2013"}, 1)
+	found(t, n, "/20130405", []string{"20130405"}, 2)
+	found(t, n, "/201304", []string{"201304"}, 3)
+	found(t, n, "/2013/x", []string{"2013"}, 4)
+	found(t, n, "/20/x", []string{"20/x"}, 5)
+
+	c := n.Clone()
+	n.Remove("/:[4]other/x")
+	if len(n.lengths[4]) != 1 || len(c.lengths[4]) != 2 {
+		t.Errorf("Length index after Remove (actual) %v, clone %v", n.lengths, c.lengths)
+	}
+	found(t, n, "/2013/x", []string{"2013/x"}, 5)
+	found(t, c, "/2013/x", []string{"2013"}, 4)
+}
+
+func BenchmarkLengthIndex(b *testing.B) {
+	n := New()
+	for i := 1; i <= 40; i++ {
+		n.Add(fmt.Sprintf("/:[%d]code/x", i), i)
+	}
+	key := "/" + strings.Repeat("a", 40) + "/x"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Find(key)
+	}
+}
@@ -0,0 +1,69 @@
+package pathtree
+
+import "strings"
+
+// Matcher finds paths in a tree like Find, reusing its buffers from one lookup
+// to the next to save allocations in hot loops. A Matcher is not safe for
+// concurrent use, use one per goroutine.
+type Matcher struct {
+	tree       *Node
+	elements   []string
+	expansions []string // the stack the expansions are built in
+	saved      []string // the expansions of the leafs found
+	spans      []Span   // the spans of the element being matched
+	query      query
+}
+
+// NewMatcher returns a Matcher finding paths in the tree below this node.
+func (n *Node) NewMatcher() *Matcher {
+	m := &Matcher{tree: n}
+	m.Reset()
+	return m
+}
+
+// Find finds a given path like Find. The expansions are only valid until the
+// next call to Find or Reset.
+func (m *Matcher) Find(key string) (leaf *Leaf, expansions []string) {
+	n := m.tree
+	key, ok := n.relative(key)
+	if !ok {
+		return nil, nil
+	}
+
+	var slashend bool
+	m.elements, slashend = appendElements(m.elements[:0], key, n.conf.sep)
+	m.query = query{strict: n.conf.strict, slashend: slashend, stack: m.expansions[:0], saved: m.saved[:0], spans: m.spans[:0]}
+	leaf, expansions = n.findPath(m.elements, slashend, &m.query)
+	m.saved, m.spans = m.query.saved, m.query.spans
+	if len(expansions) == 0 {
+		return leaf, nil
+	}
+	return leaf, expansions
+}
+
+// Reset releases the buffers of the matcher, which refer to the last path
+// looked up, eg. after an unusually long one.
+func (m *Matcher) Reset() {
+	m.elements = make([]string, 0, 16)
+	m.expansions = make([]string, 0, 16)
+	m.saved = make([]string, 0, 16)
+	m.spans = make([]Span, 0, 4)
+	m.query = query{}
+}
+
+// Appends the elements of a path starting with sep to dst like splitPath.
+func appendElements(dst []string, key, sep string) (elements []string, slashend bool) {
+	key = key[len(sep):]
+	for {
+		i := strings.Index(key, sep)
+		if i == -1 {
+			break
+		}
+		dst = append(dst, key[:i])
+		key = key[i+len(sep):]
+	}
+	if key == "" {
+		return dst, true
+	}
+	return append(dst, key), false
+}
@@ -0,0 +1,76 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	n := New(WithStrictSlash())
+	n.Add("/", 1)
+	n.Add("/users/:id", 2)
+	n.Add("/users/:id/", 3)
+	n.Add("/files/*path", 4)
+	n.Add("/a_:x;_:y;/b", 5)
+
+	m := n.NewMatcher()
+	for _, key := range []string{"/", "/users/7", "/users/7/", "/files/a//b/", "/a_1_2/b", "/missing", "users", "", "//", "/users//"} {
+		leaf, exp := n.Find(key)
+		mleaf, mexp := m.Find(key)
+		if mleaf != leaf || !reflect.DeepEqual(mexp, exp) {
+			t.Errorf("%q: (actual) %v %v != %v %v (expected)", key, mleaf, mexp, leaf, exp)
+		}
+	}
+
+	leaf, exp := m.Find("/a_1_2/b")
+	if allocs := testing.AllocsPerRun(10, func() { m.Find("/a_1_2/b") }); allocs != 0 || leaf.Value != 5 || !reflect.DeepEqual(exp, []string{"1", "2"}) {
+		t.Errorf("Find of wildcards (actual) %v allocations %v %v", allocs, leaf, exp)
+	}
+
+	if leaf, exp := n.FindAppend([]string{"a"}, "/users/7"); leaf == nil || !reflect.DeepEqual(exp, []string{"a", "7"}) {
+		t.Errorf("FindAppend (actual) %v %v", leaf, exp)
+	}
+
+	m.Reset()
+	if leaf, exp := m.Find("/users/8"); leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(exp, []string{"8"}) {
+		t.Errorf("Find after Reset (actual) %v %v", leaf, exp)
+	}
+
+	n.WithBasePath("/api")
+	for _, key := range []string{"/api/users/9", "/api", "/api/", "/users/9", "/apiusers/9"} {
+		leaf, exp := n.Find(key)
+		mleaf, mexp := m.Find(key)
+		if mleaf != leaf || !reflect.DeepEqual(mexp, exp) {
+			t.Errorf("%q: (actual) %v %v != %v %v (expected)", key, mleaf, mexp, leaf, exp)
+		}
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	n := New()
+	n.Add("/users/:id/posts/:post", 1)
+	n.Add("/static/*path", 2)
+	n.Add("/a/b/c/d/e", 3)
+	keys := []string{"/users/7/posts/9", "/static/css/site.css", "/a/b/c/d/e"}
+
+	b.Run("Find", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n.Find(keys[i%len(keys)])
+		}
+	})
+	b.Run("FindAppend", func(b *testing.B) {
+		var expansions []string
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, expansions = n.FindAppend(expansions[:0], keys[i%len(keys)])
+		}
+	})
+	b.Run("Matcher", func(b *testing.B) {
+		m := n.NewMatcher()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.Find(keys[i%len(keys)])
+		}
+	})
+}
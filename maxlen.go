@@ -0,0 +1,50 @@
+package pathtree
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Splits the length bound off a path element starting with one, like
+// "<=64>:a;-:b", returning the rest of the element and the bound, or the
+// element and 0 if it has none.
+func cutMaxLength(el string) (rest string, maxlen int) {
+	if !strings.HasPrefix(el, "<=") {
+		return el, 0
+	}
+	end := strings.IndexByte(el, '>')
+	if end == -1 {
+		return el, 0
+	}
+	maxlen, err := strconv.Atoi(el[2:end])
+	if err != nil || maxlen <= 0 {
+		return el, 0
+	}
+	return el[end+1:], maxlen
+}
+
+// Returns the length bound of a path element in the syntax cutMaxLength takes.
+func formatMaxLength(maxlen int) string {
+	if maxlen == 0 {
+		return ""
+	}
+	return "<=" + strconv.Itoa(maxlen) + ">"
+}
+
+// Checks length bounds are only used on elements they can apply to.
+func checkMaxLength(elements []string) error {
+	for _, el := range elements {
+		rest, maxlen := cutMaxLength(el)
+		if maxlen == 0 {
+			continue
+		}
+		if _, _, _, span := parseSpan(strings.TrimSuffix(rest, ";")); span || (len(rest) > 0 && rest[0] == '*') {
+			return errors.New("length bound on multi-element wildcard " + el)
+		}
+		if _, wildcards, _ := parseElement(rest); len(wildcards) == 0 {
+			return errors.New("length bound on literal " + el)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package pathtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMaxLength(t *testing.T) {
+	n := New()
+	l, err := n.Add("/ids/<=9>:a;-:b;-:c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Add("/ids/:x", 2)
+	if l.Pattern() != "/ids/<=9>:a;-:b;-:c" {
+		t.Errorf("Pattern (actual) %s", l.Pattern())
+	}
+
+	if leaf, exp := n.Find("/ids/ab-cd-ef"); leaf != l || !reflect.DeepEqual(exp, []string{"ab", "cd", "ef"}) {
+		t.Errorf("Find within bound (actual) %v %v", leaf, exp)
+	}
+	if leaf, _ := n.Find("/ids/abc-def-gh"); leaf == nil || leaf.Value != 2 {
+		t.Errorf("Find over bound should fall through (actual) %v", leaf)
+	}
+	if leaf, _ := n.Find("/ids/" + strings.Repeat("a-", 1000)); leaf == nil || leaf.Value != 2 {
+		t.Errorf("Find over bound should fall through (actual) %v", leaf)
+	}
+
+	if path, _, missing := n.Reverse(l, map[string]string{"a": "ab", "b": "cd", "c": "ef"}); path != "/ids/ab-cd-ef" || len(missing) != 0 {
+		t.Errorf("Reverse within bound (actual) %s %v", path, missing)
+	}
+	if _, _, missing := n.Reverse(l, map[string]string{"a": "abc", "b": "def", "c": "gh"}); !reflect.DeepEqual(missing, []string{"<=9>:a;-:b;-:c"}) {
+		t.Errorf("Reverse over bound (actual) %v", missing)
+	}
+
+	for _, p := range []string{"/x/<=9>abc", "/x/<=9>:3{a}", "/x/<=9>*a"} {
+		if _, err := n.Add(p, 3); err == nil {
+			t.Errorf("Add(%s) should fail", p)
+		}
+	}
+
+	if c, _ := Canonicalize("/ids/<=09>:a;-:b"); c != "/ids/<=9>:a;-:b;" {
+		t.Errorf("Canonicalize (actual) %s", c)
+	}
+	if s := CanonicalShape("/ids/<=9>:a;-:b"); s != "/ids/<=9>:0;-:1;" {
+		t.Errorf("CanonicalShape (actual) %s", s)
+	}
+	if _, err := l.Regexp(); err == nil {
+		t.Error("Regexp should fail with an element length bound")
+	}
+	if _, ok, _ := MatchSegment("<=3>:a", "abcd"); ok {
+		t.Error("MatchSegment over bound should fail")
+	}
+}
@@ -0,0 +1,16 @@
+package pathtree
+
+// SetMeta attaches metadata to the leaf under key, like a route name or tags
+// used by tooling, leaving Value for the main payload.
+func (l *Leaf) SetMeta(key string, v interface{}) {
+	if l.meta == nil {
+		l.meta = make(map[string]interface{})
+	}
+	l.meta[key] = v
+}
+
+// Meta returns the metadata attached to the leaf under key.
+func (l *Leaf) Meta(key string) (v interface{}, ok bool) {
+	v, ok = l.meta[key]
+	return v, ok
+}
@@ -0,0 +1,30 @@
+package pathtree
+
+import (
+	"testing"
+)
+
+func TestMeta(t *testing.T) {
+	n := New()
+
+	leaf, _ := n.Add("/users/:id", 1)
+	if _, ok := leaf.Meta("name"); ok {
+		t.Errorf("Expected no metadata")
+	}
+
+	leaf.SetMeta("name", "user")
+	leaf.SetMeta("tags", []string{"public"})
+	if v, ok := leaf.Meta("name"); !ok || v != "user" {
+		t.Errorf("Meta (actual) %v %v", v, ok)
+	}
+
+	c := n.Clone()
+	leaf.SetMeta("name", "changed")
+	cleaf, _ := c.Find("/users/7")
+	if v, _ := cleaf.Meta("name"); v != "user" {
+		t.Errorf("Cloned meta (actual) %v != user (expected)", v)
+	}
+	if _, ok := cleaf.Meta("tags"); !ok {
+		t.Errorf("Cloned meta is missing tags")
+	}
+}
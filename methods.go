@@ -0,0 +1,63 @@
+package pathtree
+
+import (
+	"errors"
+	"sort"
+)
+
+// AddMethod adds a value for a method to the leaf of a path, adding the path if
+// needed. A path may have a value for each of several methods, such as HTTP
+// methods, and a Value of its own if also added with Add.
+func (n *Node) AddMethod(method, key string, val interface{}) (leaf *Leaf, err error) {
+	if leaf = n.Get(key); leaf == nil {
+		if leaf, err = n.Add(key, nil); err != nil {
+			return nil, err
+		}
+	} else if _, ok := leaf.methods[method]; ok {
+		return nil, errors.New("duplicate method " + method)
+	}
+
+	if leaf.methods == nil {
+		leaf.methods = make(map[string]interface{})
+	}
+	leaf.methods[method] = val
+	return leaf, nil
+}
+
+// FindMethod finds a given path like Find among the leafs with a value for
+// method, so a leaf without it doesn't hide one of lower priority that has
+// it. Use AllowedMethods to tell a path without the method from a path not
+// found.
+func (n *Node) FindMethod(method, key string) (leaf *Leaf, expansions []string) {
+	leaf, expansions, _, _ = n.FindMethodStatus(method, key)
+	return leaf, expansions
+}
+
+// AllowedMethods returns the sorted methods of the leaf matching key whatever
+// the method looked up, eg. for the Allow header of a 405 response.
+func (n *Node) AllowedMethods(key string) []string {
+	leaf, _ := n.Find(key)
+	if leaf == nil {
+		return nil
+	}
+	return leaf.Methods()
+}
+
+// Method returns the value of the leaf for method.
+func (l *Leaf) Method(method string) (val interface{}, ok bool) {
+	val, ok = l.methods[method]
+	return val, ok
+}
+
+// Methods returns the sorted methods the leaf has a value for.
+func (l *Leaf) Methods() []string {
+	if len(l.methods) == 0 {
+		return nil
+	}
+	methods := make([]string, 0, len(l.methods))
+	for method := range l.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,50 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMethods(t *testing.T) {
+	n := New()
+	n.AddMethod("POST", "/users/:id", 1)
+	n.AddMethod("GET", "/users/:id", 2)
+	n.AddMethod("GET", "/*path", 3)
+	if _, err := n.AddMethod("GET", "/users/:id", 4); err == nil {
+		t.Errorf("Expected an error for a duplicate method")
+	}
+	if _, err := n.AddMethod("GET", "users", 4); err == nil {
+		t.Errorf("Expected an error for an invalid path")
+	}
+
+	leaf, exp := n.FindMethod("POST", "/users/7")
+	if leaf == nil || !reflect.DeepEqual(exp, []string{"7"}) {
+		t.Fatalf("FindMethod (actual) %v %v", leaf, exp)
+	}
+	if val, ok := leaf.Method("POST"); !ok || val != 1 {
+		t.Errorf("Method (actual) %v %v", val, ok)
+	}
+	if leaf, _ := n.FindMethod("DELETE", "/users/7"); leaf != nil {
+		t.Errorf("Should not have found DELETE /users/7")
+	}
+	if leaf, _ := n.FindMethod("POST", "/a/b"); leaf != nil {
+		t.Errorf("Should not have found POST /a/b")
+	}
+	n.AddMethod("PUT", "/files/:name", 5)
+	if leaf, exp := n.FindMethod("GET", "/files/a"); leaf == nil || !reflect.DeepEqual(exp, []string{"files/a"}) {
+		t.Errorf("FindMethod should fall through to the star (actual) %v %v", leaf, exp)
+	}
+
+	if methods := n.AllowedMethods("/users/7"); !reflect.DeepEqual(methods, []string{"GET", "POST"}) {
+		t.Errorf("AllowedMethods (actual) %v", methods)
+	}
+	if methods := n.AllowedMethods("/"); methods != nil {
+		t.Errorf("AllowedMethods of a missing path (actual) %v", methods)
+	}
+
+	c := n.Clone()
+	c.AddMethod("DELETE", "/users/:id", 5)
+	if methods := n.AllowedMethods("/users/7"); len(methods) != 2 {
+		t.Errorf("Clone shares methods with the original: %v", methods)
+	}
+}
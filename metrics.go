@@ -0,0 +1,43 @@
+package pathtree
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// Expvar turns on counting of hits and misses for Find on this tree and returns
+// a variable that publishes them as JSON, suitable for expvar.Publish:
+//
+//	{"hits": {"/users/:id": 12, ...}, "misses": 3}
+//
+// Hits are keyed by pattern rather than by the paths looked up, so the number of
+// keys is bounded by the number of leafs. Call it before the tree is shared
+// between goroutines.
+func (n *Node) Expvar() expvar.Var {
+	n.counts = true
+	return expvar.Func(func() interface{} {
+		hits := make(map[string]int64)
+		n.walk(func(l *Leaf) {
+			hits[l.Pattern()] = l.Hits()
+		})
+		return map[string]interface{}{"hits": hits, "misses": n.Misses()}
+	})
+}
+
+// Hits returns the number of times Find returned this leaf while counting.
+func (l *Leaf) Hits() int64 {
+	return atomic.LoadInt64(&l.hits)
+}
+
+// Misses returns the number of times Find found nothing while counting.
+func (n *Node) Misses() int64 {
+	return atomic.LoadInt64(&n.misses)
+}
+
+func (n *Node) count(leaf *Leaf) {
+	if leaf == nil {
+		atomic.AddInt64(&n.misses, 1)
+	} else {
+		atomic.AddInt64(&leaf.hits, 1)
+	}
+}
@@ -0,0 +1,33 @@
+package pathtree
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExpvar(t *testing.T) {
+	n := New()
+
+	n.Add("/users/:id", 1)
+	n.Add("/files/*path", 2)
+	n.Find("/users/1")
+
+	v := n.Expvar()
+	n.Find("/users/2")
+	n.Find("/users/3")
+	n.Find("/files/a/b")
+	n.Find("/missing/x")
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal([]byte(v.String()), &actual); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", v.String(), err)
+	}
+	expected := map[string]interface{}{
+		"hits":   map[string]interface{}{"/users/:id": 2.0, "/files/*path": 1.0},
+		"misses": 1.0,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Metrics (actual) %v != %v (expected)", actual, expected)
+	}
+}
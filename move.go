@@ -0,0 +1,85 @@
+package pathtree
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// RedirectTo is the value of the leaf Move leaves at the old pattern of a leaf.
+type RedirectTo struct {
+	Leaf *Leaf // the leaf moved
+}
+
+// Move moves the leaf of oldPattern to newPattern, keeping everything about it
+// but its pattern, like its value, metadata and hits. The new pattern must
+// have the same wildcard names in the same order, so that Reverse keeps
+// working with the same variables. If keepRedirect is set, a leaf with a
+// RedirectTo value is left at oldPattern so that old paths can be redirected.
+func (n *Node) Move(oldPattern, newPattern string, keepRedirect bool) (*Leaf, error) {
+	oldSlot := n.slot(oldPattern)
+	if oldSlot == nil || *oldSlot == nil || (*oldSlot).hidden {
+		return nil, errors.New("path not found")
+	}
+	leaf := *oldSlot
+
+	p, err := n.parse(newPattern)
+	if err != nil {
+		return nil, err
+	}
+	slot, moved := n.add(leaf.order, p, 0, nil)
+	if *slot != nil {
+		return nil, &DuplicatePathError{newPattern, (*slot).Source()}
+	}
+	moved.source = newPattern
+	if diffs := wildcardDiffs(leaf.Wildcards, moved.Wildcards); diffs != nil {
+		moved.parent.prune()
+		return nil, errors.New("incompatible wildcards: " + strings.Join(diffs, ", "))
+	}
+
+	old := *leaf
+	if keepRedirect {
+		*oldSlot = &Leaf{
+			Value:     RedirectTo{leaf},
+			Wildcards: old.Wildcards,
+			order:     old.order,
+			parent:    old.parent,
+			slashend:  old.slashend,
+			pattern:   old.pattern,
+			source:    old.source,
+		}
+	} else {
+		*oldSlot = nil
+	}
+
+	leaf.relocate(moved)
+	for _, version := range leaf.versions {
+		version.relocate(moved)
+	}
+	if leaf.failure != nil {
+		leaf.failure.relocate(moved)
+	}
+	*slot = leaf
+	old.parent.prune()
+	return leaf, nil
+}
+
+// Gives the leaf the pattern of another.
+func (l *Leaf) relocate(to *Leaf) {
+	l.Wildcards, l.parent, l.slashend, l.pattern, l.source = to.Wildcards, to.parent, to.slashend, to.pattern, to.source
+}
+
+// Returns the differences between the names of the wildcards of two patterns.
+func wildcardDiffs(old, moved []Wildcard) (diffs []string) {
+	for i := 0; i < len(old) || i < len(moved); i++ {
+		switch {
+		case i >= len(moved):
+			diffs = append(diffs, strconv.Itoa(i)+": "+strconv.Quote(old[i].Name)+" missing")
+		case i >= len(old):
+			diffs = append(diffs, strconv.Itoa(i)+": "+strconv.Quote(moved[i].Name)+" unexpected")
+		case old[i].Name != moved[i].Name:
+			diffs = append(diffs, strconv.Itoa(i)+": "+strconv.Quote(old[i].Name)+" renamed "+strconv.Quote(moved[i].Name))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,44 @@
+package pathtree
+
+import "testing"
+
+func TestMove(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/v1/users/:id", 1)
+	l1.SetMeta("name", "user")
+	n.Add("/v1/posts/:id", 2)
+	n.Add("/old/:a/:b", 3)
+
+	leaf, err := n.Move("/v1/users/:id", "/v1/accounts/:id", true)
+	if err != nil || leaf != l1 {
+		t.Fatalf("Move (actual) %v %v", leaf, err)
+	}
+	found(t, n, "/v1/accounts/7", []string{"7"}, 1)
+	if v, _ := l1.Meta("name"); v != "user" || l1.Pattern() != "/v1/accounts/:id" {
+		t.Errorf("Moved leaf (actual) %v %s", v, l1.Pattern())
+	}
+	found(t, n, "/v1/users/7", []string{"7"}, RedirectTo{l1})
+	reverse(t, n, l1, map[string]string{"id": "7"}, "/v1/accounts/7", map[string]string{}, nil)
+
+	if _, err := n.Move("/old/:a/:b", "/new/:b/:c/:d", false); err == nil ||
+		err.Error() != `incompatible wildcards: 0: "a" renamed "b", 1: "b" renamed "c", 2: "d" unexpected` {
+		t.Errorf("Expected an error listing the differences, got %v", err)
+	}
+	if _, ok := n.edges["new"]; ok {
+		t.Errorf("A failed move should leave nothing behind")
+	}
+	if _, err := n.Move("/v1/posts/:id", "/v1/accounts/:id", false); err == nil {
+		t.Errorf("Expected an error moving onto an existing pattern")
+	}
+	if _, err := n.Move("/missing", "/m", false); err == nil {
+		t.Errorf("Expected an error moving a missing pattern")
+	}
+
+	if _, err := n.Move("/old/:a/:b", "/new/:a/*b", false); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	found(t, n, "/new/x/y/z", []string{"x", "y/z"}, 3)
+	if _, ok := n.edges["old"]; ok {
+		t.Errorf("Move without a redirect should prune the old pattern")
+	}
+}
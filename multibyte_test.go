@@ -0,0 +1,96 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiBytePadding(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/記事_:id", 1)
+	l2, _ := n.Add("/a—:[3]x;—b", 2)
+	l3, _ := n.Add("/ß:[2]y;", 3)
+
+	found(t, n, "/記事_42", []string{"42"}, 1)
+	found(t, n, "/記事_", []string{""}, 1)
+	found(t, n, "/a—abc—b", []string{"abc"}, 2)
+	notfound(t, n, "/x記事_42")
+	notfound(t, n, "/記_42")
+	notfound(t, n, "/a—ab—b")
+	notfound(t, n, "/ße")
+
+	// Lengths are in bytes
+	if leaf, exp := n.Find("/a—é!—b"); leaf != l2 || exp[0] != "é!" {
+		t.Errorf("Find(/a—é!—b) (actual) %v %v", leaf, exp)
+	}
+	if leaf, exp := n.Find("/ßé"); leaf != l3 || exp[0] != "é" {
+		t.Errorf("Find(/ßé) (actual) %v %v", leaf, exp)
+	}
+	notfound(t, n, "/a—é—b")
+
+	reverse(t, n, l1, map[string]string{"id": "番号"}, "/記事_番号", map[string]string{}, nil)
+	reverse(t, n, l2, map[string]string{"x": "é!"}, "/a—é!—b", map[string]string{}, nil)
+	reverse(t, n, l3, map[string]string{"y": "ab"}, "/ßab", map[string]string{}, nil)
+}
+
+func TestMultiBytePaddingCaseInsensitive(t *testing.T) {
+	n := New(WithCaseInsensitive())
+	n.Add("/ÄRGER_:id;_Ende", 1)
+	n.Add("/K:id", 2)
+
+	for path, expected := range map[string][]string{
+		"/ärger_1_ende": {"1"},
+		"/ÄRGER_ö_ENDE": {"ö"},
+		"/k7":           {"7"},
+		"/\u212a7":      {"7"},
+	} {
+		if leaf, exp := n.Find(path); leaf == nil || !reflect.DeepEqual(exp, expected) {
+			t.Errorf("Find(%s) (actual) %v %v != %v (expected)", path, leaf, exp, expected)
+		}
+	}
+}
+
+func TestRuneLength(t *testing.T) {
+	n := New(WithRuneLength())
+	l1, _ := n.Add("/word/:[1,4]w", 1)
+	l2, _ := n.Add("/code/:[3]c", 2)
+	n.Add("/tag/:[2]a;-:[1,2]b", 3)
+	l4, _ := n.Add("/m/<=4>:[1,4]w", 4)
+	if _, err := n.Add("/d/:[3]c(äöü)", 5); err != nil {
+		t.Errorf("/d/:[3]c(äöü): Unexpected error: %v", err)
+	}
+
+	found(t, n, "/word/café", []string{"café"}, 1)
+	found(t, n, "/word/日本語", []string{"日本語"}, 1)
+	notfound(t, n, "/word/cafés")
+	found(t, n, "/code/äöü", []string{"äöü"}, 2)
+	found(t, n, "/code/abc", []string{"abc"}, 2)
+	notfound(t, n, "/code/äö")
+	notfound(t, n, "/code/äöüß")
+	found(t, n, "/tag/日本-語", []string{"日本", "語"}, 3)
+	found(t, n, "/tag/éa-ßé", []string{"éa", "ßé"}, 3)
+	notfound(t, n, "/tag/日-本語")
+	notfound(t, n, "/tag/日本-語語語")
+	if leaf, exp := n.Find("/m/café"); leaf != l4 || !reflect.DeepEqual(exp, []string{"café"}) {
+		t.Errorf("Find(/m/café) (actual) %v %v", leaf, exp)
+	}
+	notfound(t, n, "/m/cafés")
+	found(t, n, "/d/äöü", []string{"äöü"}, 5)
+
+	reverse(t, n, l1, map[string]string{"w": "naïve"}, "/word/", map[string]string{"w": "naïve"}, []string{"[1,4]w"})
+	reverse(t, n, l1, map[string]string{"w": "café"}, "/word/café", map[string]string{}, nil)
+	reverse(t, n, l2, map[string]string{"c": "äöü"}, "/code/äöü", map[string]string{}, nil)
+	reverse(t, n, l4, map[string]string{"w": "café"}, "/m/café", map[string]string{}, nil)
+
+	if _, _, ok := l2.Violation([]string{"äöü"}); ok {
+		t.Errorf("Violation should count runes")
+	}
+
+	// Bytes by default
+	n = New()
+	n.Add("/code/:[3]c", 2)
+	notfound(t, n, "/code/äöü")
+	if leaf, exp := n.Find("/code/äa"); leaf == nil || exp[0] != "äa" {
+		t.Errorf("Find(/code/äa) (actual) %v %v", leaf, exp)
+	}
+}
@@ -0,0 +1,17 @@
+package pathtree
+
+// WildcardNames returns the names of the wildcards of the leaf in the order
+// Find returns their expansions, which is the order they appear in the
+// pattern, a multi-element wildcard counting as one and the star coming last.
+// The i-th expansion is always that of the i-th name, the last one being "ext"
+// for the extension WithExtractExtension.
+func (l *Leaf) WildcardNames() []string {
+	names := make([]string, len(l.Wildcards), len(l.Wildcards)+1)
+	for i, wildcard := range l.Wildcards {
+		names[i] = wildcard.Name
+	}
+	if l.parent != nil && l.parent.conf.ext {
+		names = append(names, "ext")
+	}
+	return names
+}
@@ -0,0 +1,25 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWildcardNames(t *testing.T) {
+	n := New()
+	l, _ := n.Add("/:a/x:b;-:c;/:2-3{d}/*e", 1)
+	if names := l.WildcardNames(); !reflect.DeepEqual(names, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("WildcardNames (actual) %v", names)
+	}
+	found(t, n, "/1/x2-3/4/5/6/7", []string{"1", "2", "3", "4/5", "6/7"}, 1)
+}
+
+func TestExpansionsNotShared(t *testing.T) {
+	n := New()
+	n.Add("/:a/:b/:c/v:d/x", 1)
+	n.Add("/:a/:b/:c/*rest", 2)
+
+	// An edge matching the element without leading to a leaf must not change
+	// the expansions of the star
+	found(t, n, "/1/2/3/v4", []string{"1", "2", "3", "v4"}, 2)
+}
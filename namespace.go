@@ -0,0 +1,61 @@
+package pathtree
+
+import "errors"
+
+// AddNS adds a value for the namespace ns to the leaf of a path, adding the
+// path if needed, so that trees of several tenants can share their patterns.
+// The Value of the leaf, if also added with Add, is the default for namespaces
+// without a value of their own. Find ignores paths only added with AddNS.
+func (n *Node) AddNS(ns, key string, val interface{}) (leaf *Leaf, err error) {
+	p, err := n.parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n.leafs++
+	slot, leaf := n.add(n.leafs, p, 0, nil)
+	if *slot == nil {
+		leaf.hidden = true
+		*slot = leaf
+	}
+	leaf = *slot
+
+	if _, ok := leaf.namespaces[ns]; ok {
+		return nil, errors.New("duplicate namespace " + ns)
+	}
+	if leaf.namespaces == nil {
+		leaf.namespaces = make(map[string]interface{})
+	}
+	leaf.namespaces[ns] = val
+	return leaf, nil
+}
+
+// FindNS finds a given path like Find, returning the value of the namespace
+// ns for the leaf found, or else its Value. Paths without a value for ns nor a
+// Value of their own don't match, so that other patterns may match instead.
+func (n *Node) FindNS(ns, key string) (leaf *Leaf, expansions []string, val interface{}) {
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, pick: func(l *Leaf) *Leaf {
+		if _, ok := l.namespaces[ns]; ok || !l.hidden {
+			return l
+		}
+		return nil
+	}}
+	if leaf, expansions = n.find(elements, nil, q); leaf == nil {
+		return nil, nil, nil
+	}
+	if val, ok = leaf.namespaces[ns]; !ok {
+		val = leaf.Load()
+	}
+	return leaf, expansions, val
+}
+
+// Namespace returns the value of the leaf for the namespace ns.
+func (l *Leaf) Namespace(ns string) (val interface{}, ok bool) {
+	val, ok = l.namespaces[ns]
+	return val, ok
+}
@@ -0,0 +1,48 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamespaces(t *testing.T) {
+	n := New()
+	n.Add("/users/:id", 1)
+	n.AddNS("acme", "/users/:id", 2)
+	n.AddNS("acme", "/:page", 3)
+	n.AddNS("initech", "/:page", 4)
+	n.Add("/*rest", 6)
+	if _, err := n.AddNS("acme", "/users/:id", 5); err == nil {
+		t.Errorf("Expected an error for a duplicate namespace")
+	}
+
+	cases := []struct {
+		ns, key string
+		val     interface{}
+		exp     []string
+	}{
+		{"acme", "/users/7", 2, []string{"7"}},
+		{"initech", "/users/7", 1, []string{"7"}},
+		{"acme", "/about", 3, []string{"about"}},
+		{"initech", "/about", 4, []string{"about"}},
+		{"umbrella", "/about", 6, []string{"about"}},
+		{"umbrella", "/", nil, nil},
+	}
+	for _, c := range cases {
+		_, exp, val := n.FindNS(c.ns, c.key)
+		if val != c.val || !reflect.DeepEqual(exp, c.exp) {
+			t.Errorf("%s %s: (actual) %v %v != %v %v (expected)", c.ns, c.key, val, exp, c.val, c.exp)
+		}
+	}
+
+	found(t, n, "/about", []string{"about"}, 6)
+	found(t, n, "/users/7", []string{"7"}, 1)
+
+	leaf, _, _ := n.FindNS("acme", "/about")
+	if val, ok := leaf.Namespace("acme"); !ok || val != 3 {
+		t.Errorf("Namespace (actual) %v %v", val, ok)
+	}
+	if n.Clone().Get("/:page") == leaf {
+		t.Errorf("Clone should copy leafs")
+	}
+}
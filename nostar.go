@@ -0,0 +1,70 @@
+package pathtree
+
+import "strings"
+
+// DisableStar keeps stars from matching paths reaching this node, whether the
+// star is below the node or at a node above it like "/*all", eg. to make sure
+// no catch-all matches anything in a sensitive part of the tree. Stars can
+// still be added, and match paths not reaching the node. It only changes how
+// the tree is searched, not what can be added to it.
+func (n *Node) DisableStar() {
+	n.nostar = true
+	n.conf.nostars = true
+}
+
+// Subtree returns the node the path elements of the pattern lead to, eg. to
+// call DisableStar on, or nil if no pattern added starts with them or Compact
+// merged the node away.
+func (n *Node) Subtree(pattern string) *Node {
+	if len(pattern) == 0 || pattern[0] != n.conf.sep[0] {
+		return nil
+	}
+	elements, _, _ := n.conf.split(pattern)
+	node := n
+	for _, el := range elements {
+		edge, ok := node.edges[strings.TrimSuffix(el, ";")]
+		if !ok {
+			return nil
+		}
+		node = edge.node
+	}
+	return node
+}
+
+// Reports if stars are disabled for this node or a node above it.
+func (n *Node) disabled() bool {
+	for n.parent != nil && !n.nostar {
+		n = n.parent.parent
+	}
+	return n.nostar
+}
+
+// Reports if some edges matching the first of the elements lead to a node
+// stars are disabled for.
+func (n *Node) locked(elements []string) bool {
+	if n.nostar {
+		return true
+	}
+	if len(elements) == 0 {
+		return false
+	}
+	for _, edge := range n.sorted {
+		switch {
+		case edge.maxspan > 0:
+			for width := max(edge.minspan, 1); width <= min(edge.maxspan, len(elements)); width++ {
+				if edge.node.locked(elements[width:]) {
+					return true
+				}
+			}
+		case edge.literals != nil:
+			if len(elements) >= len(edge.literals) && edge.matchLiterals(elements) && edge.node.locked(elements[len(edge.literals):]) {
+				return true
+			}
+		default:
+			if _, ok := edge.match(elements[0], n.conf, false); ok && edge.node.locked(elements[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
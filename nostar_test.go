@@ -0,0 +1,40 @@
+package pathtree
+
+import "testing"
+
+func TestDisableStar(t *testing.T) {
+	n := New()
+	n.Add("/*all", 1)
+	n.Add("/admin/users", 2)
+	n.Add("/admin/:section/edit", 3)
+	n.Add("/admin/files/*path", 4)
+
+	admin := n.Subtree("/admin")
+	if admin == nil || n.Subtree("/admin/x") != nil || n.Subtree("admin") != nil || n.Subtree("/") != n {
+		t.Fatalf("Subtree (actual) %v", admin)
+	}
+	admin.DisableStar()
+
+	for _, key := range []string{"/admin", "/admin/", "/admin/zzz/q", "/admin/files/a", "/admin/users/x"} {
+		if leaf, _ := n.Find(key); leaf != nil {
+			t.Errorf("Should not have found: %s (actual) %v", key, leaf.Value)
+		}
+	}
+	found(t, n, "/admin/users", nil, 2)
+	found(t, n, "/admin/files/edit", []string{"files"}, 3)
+	found(t, n, "/other/x", []string{"other/x"}, 1)
+	found(t, n, "/adm", []string{"adm"}, 1)
+
+	// Stars are still matched outside of the disabled subtree once cloned
+	c := n.Clone()
+	if leaf, _ := c.Find("/admin/zzz/q"); leaf != nil {
+		t.Errorf("Clone should not have found: /admin/zzz/q")
+	}
+	found(t, c, "/other/x", []string{"other/x"}, 1)
+
+	// Registration is unaffected
+	if _, err := n.Add("/admin/static/*file", 6); err != nil {
+		t.Errorf("Add (actual) %v", err)
+	}
+	notfound(t, n, "/admin/static/a")
+}
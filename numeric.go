@@ -0,0 +1,20 @@
+package pathtree
+
+// Reports if a path element is a numeric wildcard, "#name" matching only
+// digits.
+func isNumeric(el string) bool {
+	return len(el) > 1 && el[0] == '#' && el[1] != ';'
+}
+
+// Reports if s is made of ASCII digits only, and not empty.
+func numeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
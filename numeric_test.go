@@ -0,0 +1,63 @@
+package pathtree
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNumeric(t *testing.T) {
+	n := New()
+	n.Add("/users/#id", 1)
+	n.Add("/users/:name", 2)
+	n.Add("/posts/#[1,4]id/#page?", 3)
+
+	found(t, n, "/users/42", []string{"42"}, 1)
+	found(t, n, "/users/0042/", []string{"0042"}, 1)
+	found(t, n, "/users/4a", []string{"4a"}, 2)
+	found(t, n, "/users/-1", []string{"-1"}, 2)
+	found(t, n, "/posts/7", []string{"7"}, 3)
+	found(t, n, "/posts/1234/2", []string{"1234", "2"}, 3)
+	notfound(t, n, "/posts/12345")
+	notfound(t, n, "/posts/1/x")
+
+	if leaf := n.Get("/users/#id"); leaf == nil || leaf.Value != 1 || !leaf.Wildcards[0].Numeric || leaf.Pattern() != "/users/#id" {
+		t.Errorf("Get (actual) %v", leaf)
+	}
+	leaf := n.Get("/users/#id")
+	if path, _, err := n.Reverse(leaf, map[string]string{"id": "42"}); err != nil || path != "/users/42" {
+		t.Errorf("Reverse (actual) %s %v", path, err)
+	}
+	if _, _, err := n.Reverse(leaf, map[string]string{"id": "x"}); err == nil {
+		t.Errorf("Reverse should have rejected a non numeric value")
+	}
+
+	if canonical, err := Canonicalize("/a/#[2]id;"); err != nil || canonical != "/a/#[2]id;" {
+		t.Errorf("Canonicalize (actual) %s %v", canonical, err)
+	}
+	if _, err := n.Add("/x/#[y]id", 4); err == nil {
+		t.Errorf("Should have rejected the bound of /x/#[y]id")
+	}
+	if _, err := n.Add("/x/#id(a|b)", 4); err == nil {
+		t.Errorf("Should have rejected /x/#id(a|b)")
+	}
+}
+
+func BenchmarkNumeric(b *testing.B) {
+	b.Run("Numeric", func(b *testing.B) {
+		n := New()
+		n.Add("/users/#id/posts", 1)
+		for i := 0; i < b.N; i++ {
+			n.Find("/users/1234567890/posts")
+		}
+	})
+	b.Run("Regexp", func(b *testing.B) {
+		n := New()
+		n.Add("/users/:[1,20]id/posts", 1)
+		digits := regexp.MustCompile(`^[0-9]+$`)
+		for i := 0; i < b.N; i++ {
+			if _, exp := n.Find("/users/1234567890/posts"); !digits.MatchString(exp[0]) {
+				b.Fatal("not numeric")
+			}
+		}
+	})
+}
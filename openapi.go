@@ -0,0 +1,87 @@
+package pathtree
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIParam describes a single path parameter of an OpenAPI template.
+type OpenAPIParam struct {
+	Name      string   // name of the wildcard
+	Splat     bool     // if the parameter came from a star and may contain '/'
+	MinLength int      // minLength of the parameter (0 for none)
+	MaxLength int      // maxLength of the parameter (0 for none)
+	Enum      []string // the values allowed (nil for any)
+}
+
+// OpenAPIPath is a leaf of the tree rendered as an OpenAPI path template.
+type OpenAPIPath struct {
+	Template string         // the template, eg. "/users/{id}/files/{path}"
+	Params   []OpenAPIParam // the parameters, in order they appear in the template
+	Value    interface{}    // the value associated with the leaf
+	Exact    bool           // false if the template is only a best-effort rendering
+	Warnings []string       // the reasons the template is not exact
+}
+
+// OpenAPIPaths renders every leaf in the tree as an OpenAPI path template,
+// ordered by the order the leafs were added.
+//
+// Elements made of a single wildcard become "{name}" and stars become "{name}"
+// with Splat set. Padding around wildcards and "|" alternatives can't be
+// expressed by OpenAPI, so those elements are rendered with their first
+// alternative, Exact is cleared and a warning is added for each of them.
+func (n *Node) OpenAPIPaths() []OpenAPIPath {
+	var leafs []*Leaf
+	n.walk(func(l *Leaf) { leafs = append(leafs, l) })
+	sort.Slice(leafs, func(i, j int) bool { return leafs[i].order < leafs[j].order })
+
+	paths := make([]OpenAPIPath, 0, len(leafs))
+	for _, leaf := range leafs {
+		paths = append(paths, leaf.openAPIPath())
+	}
+	return paths
+}
+
+func (l *Leaf) openAPIPath() OpenAPIPath {
+	path := OpenAPIPath{Value: l.Load(), Exact: true}
+	var template string
+	i := 0
+
+	for _, edge := range l.edges() {
+		element := ""
+		for key, value := range edge.wildcards {
+			element += edge.padding[key][0] + "{" + value.Name + "}"
+			path.Params = append(path.Params, OpenAPIParam{Name: value.Name, MinLength: value.Min, MaxLength: value.Max, Enum: value.Enum})
+		}
+		if !edge.wildend {
+			element += edge.padding[len(edge.padding)-1][0]
+		}
+
+		for _, pads := range edge.padding {
+			if len(pads) > 1 {
+				path.Exact = false
+				path.Warnings = append(path.Warnings, "element "+strconv.Itoa(i)+": alternatives "+strconv.Quote(strings.Join(pads, "|"))+" reduced to "+strconv.Quote(pads[0]))
+			}
+		}
+		if len(edge.wildcards) > 0 && element != "{"+edge.wildcards[0].Name+"}" {
+			path.Exact = false
+			path.Warnings = append(path.Warnings, "element "+strconv.Itoa(i)+": padding around wildcards in "+strconv.Quote(edge.repr))
+		}
+
+		template += "/" + element
+		i += edge.width()
+	}
+
+	if l.isStar() {
+		star := l.Wildcards[len(l.Wildcards)-1]
+		template += "/{" + star.Name + "}"
+		path.Params = append(path.Params, OpenAPIParam{Name: star.Name, Splat: true})
+	}
+	if l.slashend || template == "" {
+		template += "/"
+	}
+
+	path.Template = template
+	return path
+}
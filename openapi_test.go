@@ -0,0 +1,35 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPIPaths(t *testing.T) {
+	n := New()
+
+	n.Add("/", 1)
+	n.Add("/users/:id/files/*path", 2)
+	n.Add("/users/:[3,8]id/", 3)
+	n.Add("/Archive_:first;_all", 4)
+	n.Add("/path|road/to", 5)
+
+	paths := n.OpenAPIPaths()
+	if len(paths) != 5 {
+		t.Fatalf("Expected 5 paths, got %d", len(paths))
+	}
+
+	expected := []OpenAPIPath{
+		{Template: "/", Value: 1, Exact: true},
+		{Template: "/users/{id}/files/{path}", Value: 2, Exact: true, Params: []OpenAPIParam{{Name: "id"}, {Name: "path", Splat: true}}},
+		{Template: "/users/{id}/", Value: 3, Exact: true, Params: []OpenAPIParam{{Name: "id", MinLength: 3, MaxLength: 8}}},
+		{Template: "/Archive_{first}_all", Value: 4, Params: []OpenAPIParam{{Name: "first"}}, Warnings: []string{`element 0: padding around wildcards in "Archive_:first;_all"`}},
+		{Template: "/path/to", Value: 5, Warnings: []string{`element 0: alternatives "path|road" reduced to "path"`}},
+	}
+
+	for i, path := range paths {
+		if !reflect.DeepEqual(path, expected[i]) {
+			t.Errorf("Path %d: (actual) %+v != %+v (expected)", i, path, expected[i])
+		}
+	}
+}
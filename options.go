@@ -0,0 +1,365 @@
+package pathtree
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Option configures tree-wide behavior, see New.
+type Option func(*config)
+
+// The configuration shared by all the nodes of a tree.
+type config struct {
+	sep          string                           // the separator between path elements
+	fold         bool                             // if literals match case-insensitively
+	strict       bool                             // if trailing slashes must match
+	collapse     bool                             // if lookups treat consecutive separators as one
+	starslash    bool                             // if star expansions keep the trailing slash of the path
+	stars        StarPriority                     // how stars compete with the edges of their node
+	newest       bool                             // if the pattern added last wins rather than the first
+	maxdepth     int                              // maximum number of path elements in a pattern (0 for none)
+	maxwildcards int                              // maximum number of wildcards in a pattern (0 for none)
+	runes        bool                             // if the bounds of wildcards count runes rather than bytes
+	nonempty     bool                             // if wildcards must match at least one character
+	dots         bool                             // if wildcards stop at '.' like at a separator
+	canonical    bool                             // if patterns are normalized as by Canonicalize
+	segeq        func(pattern, input string) bool // if set, compares literals and padding with input
+	defs         map[string]Wildcard              // wildcards defined with DefineWildcard
+	failures     bool                             // if a leaf has a constraint failure leaf
+	arena        *arena                           // if set, allocates nodes, edges and leafs in chunks
+	decoders     map[string]valueDecoder          // leaf value decoders by type, registered with RegisterValueType
+	fallbacks    map[string]*Leaf                 // fallback leafs by prefix, set with SetFallback
+	onadd        func(string, *Leaf)              // if set, called with the pattern and leaf of each Add
+	ext          bool                             // if Find captures the extension of the last element as ext
+	profile      bool                             // if edges count lookups, see EnableProfile
+	nostars      bool                             // if DisableStar was called on a node of the tree
+	hotspot      int                              // the number of wildcard edges of a node onhotspot is called above
+	onhotspot    func(Hotspot)                    // if set, called when a node gets more than hotspot wildcard edges
+}
+
+// WithCaseInsensitive makes padding and literals match regardless of case.
+// Expansions keep the case of the path being looked up.
+func WithCaseInsensitive() Option {
+	return func(c *config) { c.fold = true }
+}
+
+// WithRuneLength makes the minimum and maximum lengths of wildcards, like
+// ":[1,3]id", and the length bounds of elements, like "<=8>", count runes
+// rather than bytes, eg. "café" is 4 long, not 5.
+func WithRuneLength() Option {
+	return func(c *config) { c.runes = true }
+}
+
+// WithExtractExtension makes Find cut the extension off the last element of
+// the path and return it as the last expansion, named "ext" by WildcardNames,
+// so patterns can be added without it, eg. "/users/:id" finds "/users/7.json"
+// with the expansions "7" and "json". The extension is what follows the last
+// dot, so "archive.tar.gz" has the extension "gz", and a dot the element
+// starts with doesn't count. If nothing is found without the extension, the
+// element is matched as it is with an empty extension, as it is for paths
+// ending with a slash. A literal matching the element with its extension, like
+// "/robots.txt", wins over patterns matching it without, like "/:page".
+func WithExtractExtension() Option {
+	return func(c *config) { c.ext = true }
+}
+
+// WithStrictSlash makes trailing slashes significant: a pattern ending with a
+// slash only matches paths ending with one and vice versa. Stars still match
+// either way. Patterns differing only in their trailing slash remain duplicates.
+func WithStrictSlash() Option {
+	return func(c *config) { c.strict = true }
+}
+
+// WithCollapseSlashes makes Find treat consecutive separators in the path being
+// looked up as one, eg. "/a//b" as "/a/b", so no element is empty and star
+// expansions don't have them either. Patterns with empty elements, like
+// "/a//b", can't be added then as no path would match them. Those added before
+// the option was set are kept but no longer found.
+func WithCollapseSlashes() Option {
+	return func(c *config) { c.collapse = true }
+}
+
+// WithStarSlash makes star expansions end with a slash if the path being
+// looked up does, eg. "a/b/" for "/files/a/b/" and "/files/*path", to tell a
+// directory from a file. Reverse gives such expansions back as they are.
+func WithStarSlash() Option {
+	return func(c *config) { c.starslash = true }
+}
+
+// WithPreferNewest makes the pattern added last win among those matching a
+// path rather than the first, eg. so routes added at runtime override those
+// added at startup. It can't be changed after the first Add.
+func WithPreferNewest() Option {
+	return func(c *config) { c.newest = true }
+}
+
+// StarPriority is how a star competes with the other patterns matching a path
+// below its node.
+type StarPriority int
+
+const (
+	// StarOrdered makes the pattern added first win, the default.
+	StarOrdered StarPriority = iota
+	// StarDeferred makes a star match only if no deeper pattern does.
+	StarDeferred
+	// StarEager makes a star win over any deeper pattern.
+	StarEager
+)
+
+// WithStarPriority sets how stars compete with deeper patterns, eg. for
+// "/files/*path" and "/files/a/b" added after it, "/files/a/b" finds the star
+// with StarOrdered and StarEager, and the second pattern with StarDeferred.
+func WithStarPriority(priority StarPriority) Option {
+	return func(c *config) { c.stars = priority }
+}
+
+// WithSeparator separates path elements with b instead of '/', in patterns,
+// lookups and reversed paths alike.
+func WithSeparator(b byte) Option {
+	return func(c *config) { c.sep = string(b) }
+}
+
+// WithMaxDepth makes Add reject patterns with more than depth path elements,
+// counting a star as one.
+func WithMaxDepth(depth int) Option {
+	return func(c *config) { c.maxdepth = depth }
+}
+
+// WithMaxWildcards makes Add reject patterns with more than count wildcards,
+// counting a star or a multi-element wildcard as one.
+func WithMaxWildcards(count int) Option {
+	return func(c *config) { c.maxwildcards = count }
+}
+
+// WithRequireNonEmptyWildcards makes wildcards and stars only match if they
+// would expand to at least one character, and Reverse treat empty values as
+// missing.
+func WithRequireNonEmptyWildcards() Option {
+	return func(c *config) { c.nonempty = true }
+}
+
+// WithDotSegments makes '.' delimit wildcards like a separator does, as for
+// file names. In patterns a '.' ends a wildcard name, so "/:name.:ext" needs no
+// ';', and wildcards never expand to something containing a '.'. Padding that
+// contains '.' is still matched as written and takes precedence: "/:name.xml"
+// matches "/map.xml" with name "map", and "/:name" no longer matches it at all.
+// Stars and wildcards with enumerated values may still expand to anything.
+func WithDotSegments() Option {
+	return func(c *config) { c.dots = true }
+}
+
+// WithCanonicalPatterns normalizes patterns as Canonicalize does when adding
+// them or looking them up with Get, Update and Remove, so that Pattern returns
+// the normalized form.
+func WithCanonicalPatterns() Option {
+	return func(c *config) { c.canonical = true }
+}
+
+// WithSegmentEqual compares literals and padding with the path being looked up
+// using equal instead of byte equality, eg. to match "straße" with "strasse".
+// Padding is searched by calling equal on every substring of the input, so
+// equal should be cheap. It replaces WithCaseInsensitive.
+func WithSegmentEqual(equal func(pattern, input string) bool) Option {
+	return func(c *config) { c.segeq = equal }
+}
+
+// Configure applies options to an existing tree. Options that change how
+// patterns are added, the separator, the maximum depth, dot segments and
+// canonical patterns, can't be changed once something was added to the tree.
+func (n *Node) Configure(opts ...Option) error {
+	c := *n.conf
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if n.leafs > 0 && (c.sep != n.conf.sep || c.maxdepth != n.conf.maxdepth || c.dots != n.conf.dots || c.canonical != n.conf.canonical || c.newest != n.conf.newest) {
+		return errors.New("separator, maximum depth, dot segments, canonical patterns and newest first can't be changed after the first Add")
+	}
+	*n.conf = c
+	return nil
+}
+
+// Replaces the runs of separators in a path being looked up with a single one,
+// if collapsing them.
+func (c *config) collapsed(key string) string {
+	double := c.sep + c.sep
+	if !c.collapse || !strings.Contains(key, double) {
+		return key
+	}
+	var b strings.Builder
+	b.Grow(len(key))
+	for i := strings.Index(key, double); i != -1; i = strings.Index(key, double) {
+		b.WriteString(key[:i+len(c.sep)])
+		for key = key[i:]; strings.HasPrefix(key, c.sep); {
+			key = key[len(c.sep):]
+		}
+	}
+	b.WriteString(key)
+	return b.String()
+}
+
+// Splits a pattern into its path elements, the '?' of the trailing optional
+// ones removed.
+func (c *config) split(pattern string) (elements []string, slashend bool, optional int) {
+	elements, slashend = splitPath(pattern, c.sep)
+	for optional < len(elements) && isOptional(elements[len(elements)-1-optional]) {
+		el := elements[len(elements)-1-optional]
+		elements[len(elements)-1-optional] = el[:len(el)-1]
+		optional++
+	}
+	for i, el := range elements {
+		if c.dots {
+			el = endWildcardsAtDots(el)
+		}
+		if c.canonical {
+			el = canonicalElement(el)
+		}
+		elements[i] = el
+	}
+	return elements, slashend, optional
+}
+
+// Reports if a pattern element is a wildcard marked optional with '?', which
+// must directly follow the last wildcard, as after ';' it's padding.
+func isOptional(el string) bool {
+	if len(el) < 2 || el[len(el)-1] != '?' || el[0] == '*' {
+		return false
+	}
+	start := strings.LastIndexByte(el, ':')
+	if start == -1 && el[0] == '#' {
+		start = 0
+	}
+	return start != -1 && !strings.Contains(el[start:], ";")
+}
+
+// Ends the wildcards of a pattern element with ';' where followed by a '.'
+// outside of their enumerated values.
+func endWildcardsAtDots(el string) string {
+	if len(el) > 0 && el[0] == '*' {
+		return el
+	}
+	var b strings.Builder
+	in, enum := false, false
+	for i := 0; i < len(el); i++ {
+		switch {
+		case el[i] == ':' && !in:
+			in = true
+		case el[i] == ';' && in:
+			in = false
+		case el[i] == '(' && in:
+			enum = true
+		case el[i] == ')' && in:
+			enum = false
+		case el[i] == '.' && in && !enum:
+			b.WriteByte(';')
+			in = false
+		}
+		b.WriteByte(el[i])
+	}
+	return b.String()
+}
+
+// Returns the rank of the leaf added with the given order, the leaf with the
+// lowest rank among those matching a path being found.
+func (c *config) rank(order int) int {
+	if c.newest {
+		return -order
+	}
+	return order
+}
+
+// Reports if leaf a wins over leaf b.
+func (c *config) ranks(a, b *Leaf) bool {
+	return c.rank(a.order) < c.rank(b.order)
+}
+
+// Reports if s is an allowed value for the wildcard in this tree.
+func (c *config) accepts(w *Wildcard, s string) bool {
+	return w.acceptsLength(s, c.length(s)) && c.allows(w, s)
+}
+
+// Returns the length of s as counted by the bounds of wildcards.
+func (c *config) length(s string) int {
+	if c.runes {
+		return utf8.RuneCountInString(s)
+	}
+	return len(s)
+}
+
+// Reports if s is an allowed value for a wildcard in this tree, whatever the
+// constraints of the wildcard.
+func (c *config) allows(w *Wildcard, s string) bool {
+	return (!c.nonempty || s != "") && (!c.dots || w.Enum != nil || !strings.Contains(s, "."))
+}
+
+// Returns the start and end of the first instance of pad in s, or -1. Both are
+// byte offsets, at character boundaries. Case insensitive instances can differ
+// in length from pad, like "\u212a" (Kelvin sign) and "k".
+func (c *config) index(s, pad string) (start, end int) {
+	switch {
+	case pad == "":
+		return 0, 0
+	case c.segeq != nil:
+		for start = 0; start <= len(s); start = nextRune(s, start) {
+			for end = start; end <= len(s); end = nextRune(s, end) {
+				if c.segeq(pad, s[start:end]) {
+					return start, end
+				}
+			}
+		}
+		return -1, -1
+	case c.fold:
+		runes := utf8.RuneCountInString(pad)
+		for start = 0; start < len(s); start = nextRune(s, start) {
+			end = start
+			for i := 0; i < runes && end < len(s); i++ {
+				end = nextRune(s, end)
+			}
+			if strings.EqualFold(s[start:end], pad) {
+				return start, end
+			}
+		}
+		return -1, -1
+	}
+	if start = strings.Index(s, pad); start == -1 {
+		return -1, -1
+	}
+	return start, start + len(pad)
+}
+
+// Returns the start of pad at the end of s, or -1.
+func (c *config) suffix(s, pad string) int {
+	if c.segeq == nil && !c.fold {
+		if !strings.HasSuffix(s, pad) {
+			return -1
+		}
+		return len(s) - len(pad)
+	}
+	for start := 0; start <= len(s); start = nextRune(s, start) {
+		if c.equal(pad, s[start:]) {
+			return start
+		}
+	}
+	return -1
+}
+
+// Returns the offset of the character after the one at i in s, or len(s)+1 at
+// the end of s.
+func nextRune(s string, i int) int {
+	if i >= len(s) {
+		return i + 1
+	}
+	_, size := utf8.DecodeRuneInString(s[i:])
+	return i + size
+}
+
+// Reports if a literal and a path element are equal.
+func (c *config) equal(literal, element string) bool {
+	if c.segeq != nil {
+		return c.segeq(literal, element)
+	}
+	if c.fold {
+		return strings.EqualFold(literal, element)
+	}
+	return literal == element
+}
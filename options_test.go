@@ -0,0 +1,317 @@
+package pathtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	n := New(WithCaseInsensitive())
+
+	n.Add("/Users/:id", 1)
+	n.Add("/archive_:year;.html", 2)
+	n.Add("/a/b/c", 3)
+	n.Compact()
+
+	found(t, n, "/users/Ann", []string{"Ann"}, 1)
+	found(t, n, "/USERS/ann", []string{"ann"}, 1)
+	found(t, n, "/Archive_2013.HTML", []string{"2013"}, 2)
+	found(t, n, "/A/B/c", nil, 3)
+
+	if leaf, _ := New().MustAdd("/Users", 1).Find("/users"); leaf != nil {
+		t.Errorf("Should not have matched case-insensitively by default")
+	}
+}
+
+func TestStrictSlash(t *testing.T) {
+	n := New(WithStrictSlash())
+
+	n.Add("/dir/", 1)
+	n.Add("/file", 2)
+	n.Add("/files/*path", 3)
+	n.AddVersioned("/v", 4, 1, 1)
+
+	found(t, n, "/dir/", nil, 1)
+	notfound(t, n, "/dir")
+	found(t, n, "/file", nil, 2)
+	notfound(t, n, "/file/")
+	found(t, n, "/files/a/", []string{"a"}, 3)
+	found(t, n, "/files/a", []string{"a"}, 3)
+	if leaf, _ := n.FindVersion("/v/", 1); leaf != nil {
+		t.Errorf("Should not have found /v/ with a strict slash")
+	}
+}
+
+func TestStarSlash(t *testing.T) {
+	n := New(WithStarSlash())
+
+	n.Add("/files/*path", 1)
+	n.Add("/dir/*path/", 2)
+
+	for path, expansion := range map[string]string{
+		"/files/a/b/": "a/b/",
+		"/files/a/b":  "a/b",
+		"/files/a/":   "a/",
+		"/dir/a/":     "a/",
+	} {
+		if leaf, exp := n.Find(path); leaf == nil || len(exp) != 1 || exp[0] != expansion {
+			t.Errorf("Find(%s) (actual) %v %v != %s (expected)", path, leaf, exp, expansion)
+		} else if reversed, _, _ := n.Reverse(leaf, map[string]string{"path": exp[0]}); reversed != path {
+			t.Errorf("Reverse(%s) (actual) %s", path, reversed)
+		}
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	n := New(WithSeparator('.'))
+
+	l1, _ := n.Add(".com.:domain.*rest", 1)
+	l2, _ := n.Add(".", 2)
+	if _, err := n.Add("/com", 3); err == nil {
+		t.Errorf("Expected an error for a path not starting with the separator")
+	}
+
+	found(t, n, ".com.example.www.mail", []string{"example", "www.mail"}, 1)
+	found(t, n, ".", nil, 2)
+	notfound(t, n, "/com/example/www")
+
+	reverse(t, n, l1, map[string]string{"domain": "example", "rest": "www"}, ".com.example.www", map[string]string{}, nil)
+	reverse(t, n, l2, map[string]string{}, ".", map[string]string{}, nil)
+	if l1.Pattern() != ".com.:domain.*rest" {
+		t.Errorf("Pattern (actual) %s", l1.Pattern())
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	n := New(WithMaxDepth(2))
+
+	if _, err := n.Add("/a/b", 1); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := n.Add("/a/b/c", 2); err == nil {
+		t.Errorf("Expected an error for a path that is too deep")
+	}
+	if _, err := n.Add("/a/*rest", 3); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	found(t, n, "/a/b/c", []string{"b/c"}, 3)
+}
+
+func TestMaxWildcards(t *testing.T) {
+	n := New(WithMaxWildcards(3))
+
+	for _, pattern := range []string{"/a/:b/:c;-:d", "/:a/:2{b}/*c", "/a/b", "/x/:[2]a(ab|cd)/:b;.:c;"} {
+		if _, err := n.Add(pattern, 1); err != nil {
+			t.Errorf("%s: Unexpected error: %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"/:a/:b/:c/:d", "/:a;-:b;-:c;-:d", "/:a/:b/:c/*d", "/:a/:b/:1-2{c}/:d"} {
+		if _, err := n.Add(pattern, 2); err == nil || err.Error() != "path with more than 3 wildcards" {
+			t.Errorf("%s: Expected an error for too many wildcards (actual) %v", pattern, err)
+		}
+	}
+}
+
+func TestRequireNonEmptyWildcards(t *testing.T) {
+	n := New(WithRequireNonEmptyWildcards())
+
+	l1, _ := n.Add("/is:id;really", 1)
+	n.Add("/:a/:b", 2)
+	n.Add("/x/*rest", 3)
+
+	found(t, n, "/isherereally", []string{"here"}, 1)
+	notfound(t, n, "/isreally")
+	notfound(t, n, "//now")
+	found(t, n, "/x/y", []string{"x", "y"}, 2)
+	notfound(t, n, "/x//")
+
+	reverse(t, n, l1, map[string]string{"id": ""}, "/isreally", map[string]string{"id": ""}, []string{"[0,0]id"})
+}
+
+func TestDotSegments(t *testing.T) {
+	n := New(WithDotSegments())
+
+	n.Add("/:name.xml", 1)
+	n.Add("/:first.:second", 2)
+	l3, _ := n.Add("/:name", 3)
+	n.Add("/files/*path", 4)
+	n.Add("/v/:v(1.0|2.0).json", 5)
+
+	found(t, n, "/map.xml", []string{"map"}, 1)
+	found(t, n, "/map.json", []string{"map", "json"}, 2)
+	found(t, n, "/map", []string{"map"}, 3)
+	notfound(t, n, "/map.tar.gz")
+	found(t, n, "/files/map.tar.gz", []string{"map.tar.gz"}, 4)
+	found(t, n, "/v/2.0.json", []string{"2.0"}, 5)
+
+	if n.Get("/:first;.:second") == nil {
+		t.Errorf("Get should find the pattern written with ';'")
+	}
+	reverse(t, n, l3, map[string]string{"name": "a.b"}, "/", map[string]string{"name": "a.b"}, []string{"[0,0]name"})
+}
+
+func TestSegmentEqual(t *testing.T) {
+	german := strings.NewReplacer("ß", "ss")
+	n := New(WithSegmentEqual(func(pattern, input string) bool {
+		return german.Replace(pattern) == german.Replace(input)
+	}))
+
+	n.Add("/straße/:number", 1)
+	n.Add("/a/b/c", 2)
+	n.Add("/:city;_straße_:number", 3)
+	n.Compact()
+
+	found(t, n, "/strasse/12", []string{"12"}, 1)
+	found(t, n, "/straße/12", []string{"12"}, 1)
+	found(t, n, "/a/b/c", nil, 2)
+	found(t, n, "/berlin_strasse_3", []string{"berlin", "3"}, 3)
+	notfound(t, n, "/a/b/x")
+}
+
+func TestConfigure(t *testing.T) {
+	n := New()
+
+	if err := n.Configure(WithSeparator('.')); err != nil {
+		t.Errorf("Unexpected error before the first Add: %v", err)
+	}
+	n.Add(".a", 1)
+
+	if err := n.Configure(WithSeparator('/')); err == nil {
+		t.Errorf("Expected an error changing the separator after Add")
+	}
+	if err := n.Configure(WithMaxDepth(3)); err == nil {
+		t.Errorf("Expected an error changing the maximum depth after Add")
+	}
+	if err := n.Configure(WithCaseInsensitive()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	found(t, n, ".A", nil, 1)
+}
+
+func TestStarPriority(t *testing.T) {
+	for _, priority := range []StarPriority{StarOrdered, StarDeferred, StarEager} {
+		n := New(WithStarPriority(priority))
+		n.Add("/first/second/*star", 1)
+		n.Add("/:first/*star/", 2)
+		n.Add("/*star", 3)
+		n.Add("/", 4)
+
+		found(t, n, "/", nil, 4)
+		found(t, n, "/a", []string{"a"}, 3)
+		switch priority {
+		case StarEager:
+			found(t, n, "/a/b/c", []string{"a/b/c"}, 3)
+			found(t, n, "/first/second/third", []string{"first/second/third"}, 3)
+		default:
+			found(t, n, "/a/b/c", []string{"a", "b/c"}, 2)
+			found(t, n, "/first/second/third", []string{"third"}, 1)
+		}
+	}
+
+	for priority, expected := range map[StarPriority]interface{}{StarOrdered: 1, StarDeferred: 2, StarEager: 1} {
+		n := New(WithStarPriority(priority))
+		n.Add("/files/*path", 1)
+		n.Add("/files/a/b", 2)
+		n.Add("/files/a/c", 3)
+		if leaf, _ := n.Find("/files/a/b"); leaf == nil || leaf.Value != expected {
+			t.Errorf("%d: (actual) %v != %v (expected)", priority, leaf, expected)
+		}
+		if leaf, exp := n.Find("/files/a/d"); leaf == nil || leaf.Value != 1 || exp[0] != "a/d" {
+			t.Errorf("%d: star (actual) %v %v", priority, leaf, exp)
+		}
+	}
+
+	// Stars deferred to deeper patterns at the same node only
+	n := New(WithStarPriority(StarDeferred))
+	n.Add("/a/*star", 1)
+	n.Add("/*star", 2)
+	n.Add("/a/b/c", 3)
+	found(t, n, "/a/b/c", nil, 3)
+	found(t, n, "/a/b/d", []string{"b/d"}, 1)
+	found(t, n, "/x/y", []string{"x/y"}, 2)
+}
+
+func TestPreferNewest(t *testing.T) {
+	n := New(WithPreferNewest())
+	l1, _ := n.Add("/users/:id", 1)
+	n.Add("/:kind/:id", 2)
+	n.Add("/users/:2{rest}", 3)
+	n.Add("/files/*path", 4)
+	l5, _ := n.Add("/:kind/me", 5)
+	n.Add("/files/a/*path", 6)
+
+	found(t, n, "/users/7", []string{"users", "7"}, 2)
+	found(t, n, "/users/me", []string{"users"}, 5)
+	found(t, n, "/users/a/b", []string{"a/b"}, 3)
+	found(t, n, "/files/a/b", []string{"b"}, 6)
+	found(t, n, "/files/b/c", []string{"b/c"}, 4)
+
+	leafs := n.LeavesByPriority()
+	if leafs[0] != n.Get("/files/a/*path") || leafs[1] != l5 || leafs[len(leafs)-1] != l1 {
+		t.Errorf("Leaves by priority (actual) %v", leafs)
+	}
+
+	if err := n.Configure(WithPreferNewest()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := New().MustAdd("/", 1).Configure(WithPreferNewest()); err == nil {
+		t.Errorf("Expected an error changing the order after the first Add")
+	}
+
+	// Oldest first by default
+	n = New()
+	n.Add("/users/:id", 1)
+	n.Add("/:kind/:id", 2)
+	found(t, n, "/users/7", []string{"7"}, 1)
+}
+
+func TestCollapseSlashes(t *testing.T) {
+	n := New(WithCollapseSlashes())
+	n.Add("/", 0)
+	n.Add("/a/b", 1)
+	n.Add("/:a/:b", 2)
+	n.Add("/files/*path", 3)
+	n.Add("/users/:id/edit/", 4)
+
+	cases := []struct {
+		path       string
+		value      interface{}
+		expansions []string
+	}{
+		{"/a//b", 1, nil},
+		{"//a///b", 1, nil},
+		{"//x//y", 2, []string{"x", "y"}},
+		{"/files//x///y//", 3, []string{"x/y"}},
+		{"/users//7//edit//", 4, []string{"7"}},
+		{"//", 0, nil},
+	}
+	for _, c := range cases {
+		if leaf, exp := n.Find(c.path); leaf == nil || leaf.Value != c.value || !reflect.DeepEqual(exp, c.expansions) {
+			t.Errorf("%s: (actual) %v %v != %v %v (expected)", c.path, leaf, exp, c.value, c.expansions)
+		}
+	}
+	notfound(t, n, "//now")
+	if m := n.Match("/files//x///y"); m == nil || m.Star() != "x/y" || m.Expansions[0] != "x/y" {
+		t.Errorf("Match with collapsed slashes (actual) %v", m)
+	}
+
+	// Patterns with empty elements can't be matched once collapsing
+	if _, err := n.Add("/User_:first;//:second;.:third;", 5); err == nil || err.Error() != "empty path element with collapsed separators" {
+		t.Errorf("Expected an error for an empty element, got %v", err)
+	}
+
+	n = New()
+	n.Add("/User_:first;//:second;.:third;", 5)
+	n.Add("/:a/:b", 2)
+	found(t, n, "/User_x//y.z", []string{"x", "y", "z"}, 5)
+	found(t, n, "//now", []string{"", "now"}, 2)
+	n.Configure(WithCollapseSlashes())
+	if leaf, exp := n.Find("/User_x//y.z"); leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(exp, []string{"User_x", "y.z"}) {
+		t.Errorf("/User_x//y.z: (actual) %v %v", leaf, exp)
+	}
+	notfound(t, n, "//now")
+	if leaf, exp := n.Find("/x//y"); leaf == nil || leaf.Value != 2 || !reflect.DeepEqual(exp, []string{"x", "y"}) {
+		t.Errorf("/x//y: (actual) %v %v", leaf, exp)
+	}
+}
@@ -0,0 +1,42 @@
+package pathtree
+
+// ParamInfo describes a wildcard of a pattern and where it appears.
+type ParamInfo struct {
+	Name    string   // the name of the wildcard
+	Min     int      // the minimum length (0 for none)
+	Max     int      // the maximum length (0 for none)
+	Enum    []string // the values allowed (nil for any)
+	Element int      // the zero-based index of the path element it appears in
+	Before  string   // the padding before it in its element (first alternative)
+	After   string   // the padding after it in its element (first alternative)
+	IsStar  bool     // if it's a star, matching the rest of the path
+}
+
+// Params returns the wildcards of the leaf's pattern in order, with the path
+// element they appear in and the padding around them.
+func (l *Leaf) Params() []ParamInfo {
+	params := make([]ParamInfo, 0, len(l.Wildcards))
+	element := 0
+	for _, edge := range l.edges() {
+		for key, wildcard := range edge.wildcards {
+			param := ParamInfo{
+				Name:    wildcard.Name,
+				Min:     wildcard.Min,
+				Max:     wildcard.Max,
+				Enum:    wildcard.Enum,
+				Element: element,
+				Before:  edge.padding[key][0],
+			}
+			if key+1 < len(edge.padding) {
+				param.After = edge.padding[key+1][0]
+			}
+			params = append(params, param)
+		}
+		element += edge.width()
+	}
+
+	if l.isStar() {
+		params = append(params, ParamInfo{Name: l.Wildcards[len(l.Wildcards)-1].Name, Element: element, IsStar: true})
+	}
+	return params
+}
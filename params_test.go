@@ -0,0 +1,32 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/users/:[3,8]id/files/img_:name;.jpg|jpeg/*path", 1)
+	l2, _ := n.Add("/a/b/:v(x|y)", 2)
+	l3, _ := n.Add("/", 3)
+	n.Compact()
+
+	expected := []ParamInfo{
+		{Name: "id", Min: 3, Max: 8, Element: 1},
+		{Name: "name", Element: 3, Before: "img_", After: ".jpg"},
+		{Name: "path", Element: 4, IsStar: true},
+	}
+	if params := l1.Params(); !reflect.DeepEqual(params, expected) {
+		t.Errorf("Params (actual) %+v != %+v (expected)", params, expected)
+	}
+
+	expected = []ParamInfo{{Name: "v", Enum: []string{"x", "y"}, Element: 2}}
+	if params := l2.Params(); !reflect.DeepEqual(params, expected) {
+		t.Errorf("Params of a compacted path (actual) %+v != %+v (expected)", params, expected)
+	}
+
+	if params := l3.Params(); len(params) != 0 {
+		t.Errorf("Params of the root (actual) %+v", params)
+	}
+}
@@ -0,0 +1,119 @@
+// pathtreetest provides test helpers asserting what a pathtree matches and
+// reverses, for testing route tables.
+//
+// The helpers only use the public API of pathtree, and report failures with
+// the expected lines prefixed with '-' and the actual ones with '+', along with
+// the pattern the leaf found was added with.
+package pathtreetest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Jetvp/pathtree"
+)
+
+// Case is a path looked up by RunMatrix and what it should match.
+type Case struct {
+	Path    string            // the path looked up
+	Value   interface{}       // the value expected, unless NoMatch
+	Params  map[string]string // the wildcard expansions expected by name, unless NoMatch
+	NoMatch bool              // if the path shouldn't match anything
+}
+
+// AssertMatch reports an error unless path matches a leaf with the value and
+// wildcard expansions expected. A nil params expects no expansions.
+func AssertMatch(t testing.TB, n *pathtree.Node, path string, value interface{}, params map[string]string) bool {
+	t.Helper()
+	m := n.Match(path)
+	if m == nil {
+		t.Errorf("%s: no match, expected value %v", path, value)
+		return false
+	}
+
+	var diff []string
+	if !reflect.DeepEqual(m.Leaf.Value, value) {
+		diff = append(diff, fmt.Sprintf("-value: %v", value), fmt.Sprintf("+value: %v", m.Leaf.Value))
+	}
+	diff = append(diff, diffParams(params, m.Params())...)
+	if len(diff) > 0 {
+		t.Errorf("%s: matched %q\n\t%s", path, m.Leaf.Source(), strings.Join(diff, "\n\t"))
+		return false
+	}
+	return true
+}
+
+// AssertNoMatch reports an error if path matches a leaf.
+func AssertNoMatch(t testing.TB, n *pathtree.Node, path string) bool {
+	t.Helper()
+	if m := n.Match(path); m != nil {
+		t.Errorf("%s: matched %q with value %v, expected no match", path, m.Leaf.Source(), m.Leaf.Value)
+		return false
+	}
+	return true
+}
+
+// AssertReverse reports an error unless reversing the leaf with the variables
+// gives the path expected, with no wildcard missing.
+func AssertReverse(t testing.TB, leaf *pathtree.Leaf, vars map[string]string, path string) bool {
+	t.Helper()
+	result, err := leaf.ReverseInfo(vars)
+	if err != nil {
+		t.Errorf("%q: %v", leaf.Source(), err)
+		return false
+	}
+	if result.Path != path {
+		t.Errorf("%q: reversed\n\t-%s\n\t+%s", leaf.Source(), path, result.Path)
+		return false
+	}
+	return true
+}
+
+// RunMatrix looks up the path of every case, asserting it matches as expected
+// like AssertMatch or AssertNoMatch. Returns false if any didn't.
+func RunMatrix(t testing.TB, n *pathtree.Node, cases []Case) bool {
+	t.Helper()
+	ok := true
+	for _, c := range cases {
+		if c.NoMatch {
+			ok = AssertNoMatch(t, n, c.Path) && ok
+		} else {
+			ok = AssertMatch(t, n, c.Path, c.Value, c.Params) && ok
+		}
+	}
+	return ok
+}
+
+// Returns the lines of the expected and actual expansions that differ, by
+// wildcard name.
+func diffParams(expected, actual map[string]string) []string {
+	names := make([]string, 0, len(expected)+len(actual))
+	for name := range expected {
+		names = append(names, name)
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diff []string
+	for _, name := range names {
+		want, wanted := expected[name]
+		got, found := actual[name]
+		if wanted && found && want == got {
+			continue
+		}
+		if wanted {
+			diff = append(diff, fmt.Sprintf("-%s: %q", name, want))
+		}
+		if found {
+			diff = append(diff, fmt.Sprintf("+%s: %q", name, got))
+		}
+	}
+	return diff
+}
@@ -0,0 +1,64 @@
+package pathtreetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jetvp/pathtree"
+)
+
+// Records the errors reported instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	n := pathtree.New()
+	n.Add("/users/:id;.:format", 2)
+	users, _ := n.Add("/users/:id", 1)
+	n.Add("/files/*path", 3)
+
+	ok := RunMatrix(t, n, []Case{
+		{Path: "/users/7", Value: 1, Params: map[string]string{"id": "7"}},
+		{Path: "/users/7.json", Value: 2, Params: map[string]string{"id": "7", "format": "json"}},
+		{Path: "/files/a/b", Value: 3, Params: map[string]string{"path": "a/b"}},
+		{Path: "/missing", NoMatch: true},
+	})
+	if !ok || !AssertReverse(t, users, map[string]string{"id": "7"}, "/users/7") {
+		t.Errorf("Assertions should have passed")
+	}
+
+	r := &recorder{TB: t}
+	ok = RunMatrix(r, n, []Case{
+		{Path: "/users/7", Value: 2, Params: map[string]string{"id": "8"}},
+		{Path: "/users/7.json", Value: 2},
+		{Path: "/files/a", NoMatch: true},
+		{Path: "/missing", Value: 1},
+	})
+	AssertReverse(r, users, map[string]string{"id": "7"}, "/users/8")
+	AssertReverse(r, users, nil, "/users/7")
+
+	expected := []string{
+		"/users/7: matched \"/users/:id\"\n\t-value: 2\n\t+value: 1\n\t-id: \"8\"\n\t+id: \"7\"",
+		"/users/7.json: matched \"/users/:id;.:format\"\n\t+format: \"json\"\n\t+id: \"7\"",
+		"/files/a: matched \"/files/*path\" with value 3, expected no match",
+		"/missing: no match, expected value 1",
+		"\"/users/:id\": reversed\n\t-/users/8\n\t+/users/7",
+		"\"/users/:id\": missing wildcards [0,0]id",
+	}
+	if ok || len(r.errors) != len(expected) {
+		t.Fatalf("Errors (actual) %q", r.errors)
+	}
+	for i, msg := range r.errors {
+		if msg != expected[i] {
+			t.Errorf("Error %d (actual) %q != %q (expected)", i, msg, expected[i])
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package pathtree
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Pattern is a pattern parsed by CompilePattern, which AddPattern adds to trees
+// without parsing it again, eg. to add the same patterns to many trees. It's
+// never changed, so it can be shared between trees and goroutines.
+type Pattern struct {
+	source    string           // the pattern as passed to CompilePattern
+	elements  []string         // the path elements of the pattern
+	parsed    []patternElement // the parsed path elements, up to a star
+	slashend  bool             // if the pattern ends with a slash
+	optional  int              // the number of trailing elements marked optional
+	wildcards int              // the number of wildcards, stars included
+	defined   bool             // if it references wildcards of DefineWildcard
+	sep       string           // the separator it was parsed with
+	dots      bool             // if it was parsed for WithDotSegments
+	canonical bool             // if it was parsed for WithCanonicalPatterns
+}
+
+// A parsed path element of a pattern.
+type patternElement struct {
+	repr    string  // the element without a trailing ';'
+	segment Segment // the element to match, unless a star
+	minspan int     // the minimum number of path elements of a multi-element wildcard
+	maxspan int     // if not 0, the maximum number of path elements of a multi-element wildcard
+	star    bool    // if the element is a star
+}
+
+// CompilePattern parses a pattern for AddPattern as Add would for a tree
+// created with New without options. Trees with another separator, dot
+// segments or canonical patterns parse it again when it's added.
+func CompilePattern(pattern string) (*Pattern, error) {
+	return (&config{sep: "/"}).compile(pattern)
+}
+
+// String returns the pattern as passed to CompilePattern.
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Parses a pattern with the options of a tree.
+func (c *config) compile(key string) (*Pattern, error) {
+	if len(key) == 0 || key[0] != c.sep[0] {
+		return nil, errors.New("Path must begin with " + c.sep)
+	}
+	if err := checkSyntax(key, c.sep); err != nil {
+		return nil, err
+	}
+	elements, slashend, optional := c.split(key)
+	if len(elements) > 0 && strings.HasPrefix(elements[len(elements)-1], "**") {
+		return nil, errors.New("star name can't begin with *")
+	}
+	if slices.ContainsFunc(elements[:len(elements)-optional], isOptional) {
+		return nil, errors.New("only the last elements can be optional")
+	}
+	if err := checkMaxLength(elements); err != nil {
+		return nil, err
+	}
+
+	p := &Pattern{source: key, elements: elements, slashend: slashend, optional: optional, sep: c.sep, dots: c.dots, canonical: c.canonical}
+	p.parsed = make([]patternElement, 0, len(elements))
+	for _, el := range elements {
+		if len(el) > 0 && el[0] == '*' {
+			p.parsed = append(p.parsed, patternElement{repr: el, star: true})
+			p.wildcards++
+			break
+		}
+
+		el = strings.TrimSuffix(el, ";")
+		parsed := patternElement{repr: el, segment: newSegment(el)}
+		minspan, maxspan, name, span := parseSpan(el)
+		if span {
+			parsed.segment = Segment{padding: [][]string{{""}}, wildcards: []Wildcard{{Name: name}}, wildend: true}
+			parsed.minspan, parsed.maxspan = minspan, maxspan
+		}
+		for _, wildcard := range parsed.segment.wildcards {
+			if len(wildcard.Name) > 0 && wildcard.Name[0] == '@' {
+				p.defined = true
+			}
+		}
+		p.wildcards += len(parsed.segment.wildcards)
+		p.parsed = append(p.parsed, parsed)
+	}
+	if optional > 0 && len(p.parsed) < len(elements) {
+		return nil, errors.New("star can't be followed by optional elements")
+	}
+	return p, nil
+}
+
+// Returns the pattern without its last optional elements, if it has as many.
+func (p *Pattern) without(optional int) *Pattern {
+	q := *p
+	q.elements = p.elements[:len(p.elements)-optional]
+	q.parsed = p.parsed[:len(p.parsed)-optional]
+	q.optional = 0
+	return &q
+}
+
+// Reports if the pattern was parsed with the options of this tree.
+func (c *config) compiled(p *Pattern) bool {
+	return p.sep == c.sep && p.dots == c.dots && p.canonical == c.canonical
+}
@@ -0,0 +1,73 @@
+package pathtree
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAddPattern(t *testing.T) {
+	users, err := CompilePattern("/users/:@id/:[2,3]kind;s/")
+	if err != nil || users.String() != "/users/:@id/:[2,3]kind;s/" {
+		t.Fatalf("CompilePattern (actual) %v %v", users, err)
+	}
+	files, _ := CompilePattern("/files/:2-3{dirs}/*path")
+	if _, err := CompilePattern("users"); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+
+	trees := make([]*Node, 8)
+	var wg sync.WaitGroup
+	for i := range trees {
+		trees[i] = New()
+		trees[i].DefineWildcard("id", Wildcard{Min: 1, Max: i + 1})
+		wg.Add(1)
+		go func(n *Node) {
+			defer wg.Done()
+			n.AddPattern(users, 1)
+			n.AddPattern(files, 2)
+		}(trees[i])
+	}
+	wg.Wait()
+	for i, n := range trees {
+		found(t, n, "/users/7/docs/", []string{"7", "doc"}, 1)
+		found(t, n, "/files/a/b/c.txt", []string{"a/b", "c.txt"}, 2)
+		if leaf := n.Get("/users/:@id/:[2,3]kind;s/"); leaf == nil || leaf.Wildcards[0].Max != i+1 || leaf.Source() != users.String() {
+			t.Errorf("%d: Wildcards (actual) %v", i, leaf)
+		}
+		if _, err := n.AddPattern(users, 3); err == nil {
+			t.Errorf("%d: Expected an error for a duplicate pattern", i)
+		}
+	}
+	if w := users.parsed[1].segment.wildcards[0]; w.Name != "@id" || w.Max != 0 {
+		t.Errorf("AddPattern changed the pattern (actual) %v", w)
+	}
+
+	// Trees with other options parse the pattern again
+	n := New(WithDotSegments(), WithMaxDepth(2))
+	n.DefineWildcard("id", Wildcard{})
+	if _, err := n.AddPattern(users, 1); err == nil {
+		t.Errorf("Expected an error for a pattern deeper than the maximum")
+	}
+	named, _ := CompilePattern("/:name.json")
+	n.AddPattern(named, 2)
+	found(t, n, "/a.json", []string{"a"}, 2)
+	notfound(t, n, "/a.b.json")
+	if _, err := New().AddPattern(users, 1); err == nil || err.Error() != "undefined wildcard @id" {
+		t.Errorf("Expected an error for an undefined wildcard, got %v", err)
+	}
+}
+
+func BenchmarkAddPattern(b *testing.B) {
+	patterns := make([]*Pattern, 20)
+	for i := range patterns {
+		patterns[i], _ = CompilePattern(fmt.Sprintf("/api/v%d/:[1,8]kind;s/:id/edit", i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := New()
+		for _, p := range patterns {
+			n.AddPattern(p, i)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package pathtree
+
+// The wildcard edges of a node starting with padding, like "acme_:id", by
+// their first padding, so find only matches those whose padding the path
+// element starts with rather than all of them.
+type padIndex struct {
+	edges   map[string][]*Edge // the edges by their first padding
+	lengths map[int]int        // the number of keys of edges by length
+	scan    []*Edge            // the other edges of the node
+}
+
+// Adds the edge to the edges of the node indexed by padding, if it's a
+// wildcard edge starting with a single padding.
+func (n *Node) indexPrefix(edge *Edge) {
+	if edge.maxspan > 0 || edge.literals != nil || len(edge.wildcards) == 0 {
+		return
+	}
+	if len(edge.padding[0]) != 1 || edge.padding[0][0] == "" {
+		return
+	}
+	if n.padded == nil {
+		n.padded = &padIndex{edges: make(map[string][]*Edge), lengths: make(map[int]int)}
+	}
+	edge.prefix = edge.padding[0][0]
+	if len(n.padded.edges[edge.prefix]) == 0 {
+		n.padded.lengths[len(edge.prefix)]++
+	}
+	n.padded.edges[edge.prefix] = append(n.padded.edges[edge.prefix], edge)
+	n.padded.rescan(n.sorted)
+}
+
+// Removes the edge from the edges of the node indexed by padding.
+func (n *Node) unindexPrefix(edge *Edge) {
+	if edge.prefix == "" || n.padded == nil {
+		return
+	}
+	edges := n.padded.edges[edge.prefix]
+	for i, e := range edges {
+		if e == edge {
+			n.padded.edges[edge.prefix] = append(edges[:i:i], edges[i+1:]...)
+			break
+		}
+	}
+	if len(n.padded.edges[edge.prefix]) > 0 {
+		return
+	}
+	delete(n.padded.edges, edge.prefix)
+	if n.padded.lengths[len(edge.prefix)]--; n.padded.lengths[len(edge.prefix)] == 0 {
+		delete(n.padded.lengths, len(edge.prefix))
+	}
+	if len(n.padded.edges) == 0 {
+		n.padded = nil
+	}
+}
+
+// Collects the edges not indexed by padding, if there is an index.
+func (p *padIndex) rescan(sorted []*Edge) {
+	if p == nil {
+		return
+	}
+	p.scan = p.scan[:0]
+	for _, edge := range sorted {
+		if edge.prefix == "" {
+			p.scan = append(p.scan, edge)
+		}
+	}
+}
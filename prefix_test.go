@@ -0,0 +1,58 @@
+package pathtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrefixIndex(t *testing.T) {
+	n := New()
+	n.Add("/t/acme_:id", 1)
+	n.Add("/t/ac_:id;_x", 2)
+	n.Add("/t/globex_:id/y", 3)
+	n.Add("/t/History_|Log_:id", 4)
+	n.Add("/t/:name", 5)
+	n.Add("/t/zz/w", 6)
+
+	if len(n.edges["t"].node.padded.edges) != 3 || len(n.edges["t"].node.padded.lengths) != 3 {
+		t.Errorf("Padding index (actual) %v", n.edges["t"].node.padded)
+	}
+
+	found(t, n, "/t/acme_7", []string{"7"}, 1)
+	found(t, n, "/t/ac_7_x", []string{"7"}, 2)
+	found(t, n, "/t/globex_7/y", []string{"7"}, 3)
+	found(t, n, "/t/Log_7", []string{"7"}, 4)
+	found(t, n, "/t/ac", []string{"ac"}, 5)
+	notfound(t, n, "/t/acme_7/y")
+
+	c := n.Clone()
+	n.Remove("/t/globex_:id/y")
+	if len(n.edges["t"].node.padded.edges) != 2 || len(c.edges["t"].node.padded.edges) != 3 {
+		t.Errorf("Padding index after Remove (actual) %v, clone %v", n.edges["t"].node.padded, c.edges["t"].node.padded)
+	}
+	notfound(t, n, "/t/globex_7/y")
+	found(t, c, "/t/globex_7/y", []string{"7"}, 3)
+	found(t, c, "/t/zz/w", nil, 6)
+
+	n.Remove("/t/acme_:id")
+	n.Remove("/t/ac_:id;_x")
+	if n.edges["t"].node.padded != nil {
+		t.Errorf("Padding index without padded edges (actual) %v", n.edges["t"].node.padded)
+	}
+
+	// Padding compared case insensitively bypasses the index
+	n = New(WithCaseInsensitive())
+	n.Add("/t/acme_:id", 1)
+	found(t, n, "/t/ACME_7", []string{"7"}, 1)
+}
+
+func BenchmarkPrefixIndex(b *testing.B) {
+	n := New()
+	for i := 0; i < 4000; i++ {
+		n.Add(fmt.Sprintf("/t/tenant%d_:id", i), i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Find("/t/tenant3999_7")
+	}
+}
@@ -0,0 +1,85 @@
+package pathtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// LeavesByPriority returns every leaf in the tree, stars included, in the
+// order Find prefers them when several match a path, which is the order they
+// were added, or the reverse WithPreferNewest. Leafs of the same order, like
+// versions, are in the order of Walk.
+func (n *Node) LeavesByPriority() []*Leaf {
+	var leafs []*Leaf
+	n.Walk(func(l *Leaf) {
+		leafs = append(leafs, l)
+	})
+	sort.SliceStable(leafs, func(i, j int) bool { return n.conf.ranks(leafs[i], leafs[j]) })
+	return leafs
+}
+
+// LeavesUnder returns the leafs which can match paths starting with the path
+// elements of prefix, in the order of LeavesByPriority. Besides the leafs of
+// patterns starting with prefix, these are the leafs reached through wildcard
+// edges matching its elements, eg. "/:v;api/x" for "/api", and the stars of
+// the nodes the elements lead through, like "/*all". Prefixes end at element
+// boundaries, so "/api" isn't a prefix of "/apis", but can end within
+// multi-element wildcards and literals merged by Compact.
+func (n *Node) LeavesUnder(prefix string) []*Leaf {
+	elements, _, ok := n.lookup(prefix)
+	if !ok {
+		return nil
+	}
+
+	var leafs []*Leaf
+	seen := make(map[*Leaf]bool)
+	collect := func(l *Leaf) {
+		if !seen[l] {
+			seen[l] = true
+			leafs = append(leafs, l)
+		}
+	}
+	n.under(elements, collect)
+	sort.SliceStable(leafs, func(i, j int) bool { return n.conf.ranks(leafs[i], leafs[j]) })
+	return leafs
+}
+
+// Calls fn for the leafs below this node which can match paths starting with
+// the elements, like reach does for nodes.
+func (n *Node) under(elements []string, fn func(*Leaf)) {
+	if len(elements) == 0 {
+		n.walk(fn)
+		return
+	}
+	if n.star != nil {
+		n.star.each(fn)
+	}
+
+	for _, edge := range n.sortedEdges() {
+		switch {
+		case edge.maxspan > 0:
+			if len(elements) < edge.maxspan {
+				edge.node.walk(fn)
+			}
+			for count := edge.minspan; count <= edge.maxspan && count <= len(elements); count++ {
+				if n.conf.accepts(&edge.wildcards[0], strings.Join(elements[:count], n.conf.sep)) {
+					edge.node.under(elements[count:], fn)
+				}
+			}
+		case edge.literals != nil:
+			width := min(len(elements), len(edge.literals))
+			if !edge.matchLiterals(elements[:width]) {
+				continue
+			}
+			if width < len(edge.literals) {
+				edge.node.walk(fn)
+			} else {
+				edge.node.under(elements[width:], fn)
+			}
+		default:
+			if _, ok := edge.match(elements[0], n.conf, false); ok {
+				edge.node.under(elements[1:], fn)
+			}
+		}
+	}
+}
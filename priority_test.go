@@ -0,0 +1,65 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeavesByPriority(t *testing.T) {
+	n := New()
+	n.Add("/b/*rest", 1)
+	n.Add("/a/:id", 2)
+	n.Add("/", 3)
+	n.Add("/a/x", 4)
+
+	var values []interface{}
+	for _, leaf := range n.LeavesByPriority() {
+		values = append(values, leaf.Value)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, 2, 3, 4}) {
+		t.Errorf("LeavesByPriority (actual) %v", values)
+	}
+	if leaf, _ := n.Find("/a/x"); leaf.Value != 2 {
+		t.Errorf("Find should prefer the first leaf (actual) %v", leaf.Value)
+	}
+}
+
+func TestLeavesUnder(t *testing.T) {
+	n := New()
+	n.Add("/*all", 1)
+	n.Add("/api/users/:id", 2)
+	n.Add("/:v;api/status", 3)
+	n.Add("/api|rpc/health", 4)
+	n.Add("/apis/list", 5)
+	n.Add("/api/files/*path", 6)
+	n.Add("/api", 7)
+	n.Add("/:2{ns}/x", 8)
+	n.Add("/api/:[4]x", 9)
+	n.Add("/other/:x", 10)
+	n.Add("/:[2]short/y", 11)
+	n.Add("/api/a/b/c", 12)
+	n.Compact()
+
+	var values []interface{}
+	for _, leaf := range n.LeavesUnder("/api") {
+		values = append(values, leaf.Value)
+	}
+	if expected := []interface{}{1, 2, 3, 4, 6, 7, 8, 9, 12}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("LeavesUnder (actual) %v != %v (expected)", values, expected)
+	}
+
+	values = nil
+	for _, leaf := range n.LeavesUnder("/api/a/b/") {
+		values = append(values, leaf.Value)
+	}
+	if expected := []interface{}{1, 12}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("LeavesUnder a compacted edge (actual) %v != %v (expected)", values, expected)
+	}
+
+	if leafs := n.LeavesUnder("api"); leafs != nil {
+		t.Errorf("LeavesUnder a relative path (actual) %v", leafs)
+	}
+	if leafs := n.LeavesUnder("/"); len(leafs) != 12 {
+		t.Errorf("LeavesUnder the root (actual) %d leafs", len(leafs))
+	}
+}
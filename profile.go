@@ -0,0 +1,86 @@
+package pathtree
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// EdgeProfile is what was counted for an edge of the tree since EnableProfile.
+type EdgeProfile struct {
+	Path     string // the patterns of the path elements up to the edge, eg. "/users/:id"
+	Examined int64  // the number of path elements matched against the edge
+	Matched  int64  // the number of those the edge matched
+	Pruned   int64  // the number of times the edge was skipped as no pattern below it could win
+}
+
+// The counters of an edge, updated atomically.
+type profile struct {
+	examined, matched, pruned atomic.Int64
+}
+
+// EnableProfile turns on counting how often lookups examine, match and skip
+// each edge of the tree, edges added later included, for Profile. Lookups
+// don't count anything until it's called. Call it before the tree is shared
+// between goroutines.
+func (n *Node) EnableProfile() {
+	n.conf.profile = true
+	n.eachEdge(func(e *Edge) {
+		if e.stats == nil {
+			e.stats = new(profile)
+		}
+	})
+}
+
+// Profile returns the counts of the edges below this node since EnableProfile,
+// the most examined first, or nil if it wasn't called.
+func (n *Node) Profile() []EdgeProfile {
+	var profiles []EdgeProfile
+	n.profile("", &profiles)
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].Examined > profiles[j].Examined })
+	return profiles
+}
+
+func (n *Node) profile(path string, profiles *[]EdgeProfile) {
+	for _, edge := range n.sortedEdges() {
+		path := path + n.conf.sep + edge.repr
+		if edge.stats != nil {
+			*profiles = append(*profiles, EdgeProfile{path, edge.stats.examined.Load(), edge.stats.matched.Load(), edge.stats.pruned.Load()})
+		}
+		edge.node.profile(path, profiles)
+	}
+}
+
+// Count the edge being examined, matched or pruned, if profiling.
+func (p *profile) examine() {
+	if p != nil {
+		p.examined.Add(1)
+	}
+}
+
+func (p *profile) match() {
+	if p != nil {
+		p.matched.Add(1)
+	}
+}
+
+func (p *profile) prune() {
+	if p != nil {
+		p.pruned.Add(1)
+	}
+}
+
+// Returns the counters of a new edge, if profiling.
+func (c *config) newProfile() *profile {
+	if c.profile {
+		return new(profile)
+	}
+	return nil
+}
+
+// Calls fn for every edge below this node.
+func (n *Node) eachEdge(fn func(*Edge)) {
+	for _, edge := range n.edges {
+		fn(edge)
+		edge.node.eachEdge(fn)
+	}
+}
@@ -0,0 +1,49 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	n := New()
+	n.Add("/a/b", 1)
+	n.Add("/a/:[1]x", 2)
+
+	if profiles := n.Profile(); profiles != nil {
+		t.Errorf("Profile before EnableProfile (actual) %v", profiles)
+	}
+
+	n.EnableProfile()
+	for _, key := range []string{"/a/b", "/a/c", "/a/dd"} {
+		n.Find(key)
+	}
+
+	// Fixed length wildcards come last, so ":[1]x" is skipped once "b" matched
+	expected := []EdgeProfile{
+		{"/a", 3, 3, 0},
+		{"/a/b", 3, 1, 0},
+		{"/a/:[1]x", 1, 1, 1},
+	}
+	if profiles := n.Profile(); !reflect.DeepEqual(profiles, expected) {
+		t.Errorf("Profile (actual) %v != %v (expected)", profiles, expected)
+	}
+
+	// Edges added later are counted too
+	n.Add("/z/y", 3)
+	n.Find("/a/b")
+	n.Find("/z/y")
+	var z EdgeProfile
+	for _, profile := range n.Profile() {
+		if profile.Path == "/z" {
+			z = profile
+		}
+	}
+	if z.Examined+z.Pruned != 2 || z.Matched != 1 {
+		t.Errorf("Profile of an edge added later (actual) %v", z)
+	}
+
+	if profiles := n.Clone().Profile(); len(profiles) != 5 || profiles[0].Examined != 0 {
+		t.Errorf("Profile of a clone (actual) %v", profiles)
+	}
+}
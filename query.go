@@ -0,0 +1,121 @@
+package pathtree
+
+import "context"
+
+// Per lookup state threaded through find. A nil query is a plain Find.
+type query struct {
+	pick     func(*Leaf) *Leaf // selects the leaf to use among a path's leaf and its versions
+	strict   bool              // if the trailing slash of leafs must match
+	slashend bool              // if the path being looked up ends with a slash
+	relaxed  bool              // if the constraints of wildcards are ignored
+	trace    bool              // if the deepest node reached is recorded
+	deepest  *Node             // the deepest node reached, if tracing
+	left     int               // the number of elements left at the deepest node
+	ctx      context.Context   // if set, the lookup stops once it's done
+	err      error             // the error of ctx, once the lookup stopped
+	steps    int               // # nodes visited, if ctx is set
+	path     string            // if set, the path star expansions are sliced from
+	starlen  int               // the length of the star expansion found, if path is set
+	reject   bool              // if leafs matched but not usable are recorded
+	rejected []*Leaf           // the leafs matched but not usable, if reject is set
+	literal  bool              // if the last element only matches literal edges
+	raw      []string          // the expansions found before transforms, if path is set
+	stack    []string          // if set, the buffer expansions are appended to in place
+	saved    []string          // the expansions of the leafs found so far, if stack is set
+	spans    []Span            // the spans of the element being matched, if stack is set
+}
+
+// Returns exp with values appended. With a stack the edges of a node append
+// theirs in place, overwriting those of the previous edge, else to a copy of
+// exp so one edge can't overwrite those of another.
+func (q *query) extend(exp []string, values ...string) []string {
+	if q != nil && q.stack != nil {
+		return append(exp, values...)
+	}
+	return append(exp[:len(exp):len(exp)], values...)
+}
+
+// Returns expansions where the edges searched next can't overwrite them, the
+// saved expansions of the query if it has a stack.
+func (q *query) keep(expansions []string) []string {
+	if q == nil || q.stack == nil || len(expansions) == 0 {
+		return expansions
+	}
+	start := len(q.saved)
+	q.saved = append(q.saved, expansions...)
+	return q.saved[start:len(q.saved):len(q.saved)]
+}
+
+// The number of nodes visited between checks of the context of a lookup.
+const checkInterval = 64
+
+// Reports if the lookup should stop as its context is done, checking the
+// context every checkInterval nodes.
+func (q *query) cancelled() bool {
+	if q.err == nil && q.steps%checkInterval == 0 {
+		q.err = q.ctx.Err()
+	}
+	q.steps++
+	return q.err != nil
+}
+
+// Returns the star expansion of the remaining elements, which end the path being
+// looked up, as a substring of the path rather than joining them.
+func (q *query) star(elements []string, sep string, starslash bool) string {
+	length := len(sep) * (len(elements) - 1)
+	for _, el := range elements {
+		length += len(el)
+	}
+	end := len(q.path)
+	if q.slashend {
+		end -= len(sep)
+	}
+	start := end - length
+	if q.slashend && starslash {
+		end += len(sep)
+	}
+	return q.path[start:end]
+}
+
+// Records reaching node n with left elements still to match.
+func (q *query) visit(n *Node, left int) {
+	if q.deepest == nil || left < q.left {
+		q.deepest, q.left = n, left
+	}
+}
+
+// Returns the leaf find should use for the leaf of a path, or nil if the path
+// has no usable leaf for this lookup.
+func (q *query) resolve(l *Leaf) *Leaf {
+	if l == nil {
+		return nil
+	}
+	matched := l
+	if q != nil && q.pick != nil {
+		l = q.pick(l)
+	} else if l.hidden {
+		return nil
+	}
+	if q != nil && q.strict && l != nil && l.slashend != q.slashend && !l.isStar() {
+		return nil
+	}
+	if l == nil || !l.enabled() || (l.alias != nil && !l.alias.enabled()) {
+		if q != nil && q.reject && !matched.hidden {
+			q.rejected = append(q.rejected, matched)
+		}
+		return nil
+	} else if l.alias != nil {
+		return l.alias
+	}
+	return l
+}
+
+// Calls fn for this leaf unless it's hidden, and for each of its versions.
+func (l *Leaf) each(fn func(*Leaf)) {
+	if !l.hidden {
+		fn(l)
+	}
+	for _, version := range l.versions {
+		fn(version)
+	}
+}
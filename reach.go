@@ -0,0 +1,75 @@
+package pathtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// Canonical returns every node of the tree reached by the path elements from
+// this node, through literal and wildcard edges alike, in the order of the
+// patterns through them, eg. for "/path/to/nowhere" added before
+// "/path/:i/nowhere" the node after "to" comes before the one after ":i" for
+// the elements "path" and "to". A node's place is that of the first pattern
+// added through it, the one Find prefers. Elements ending within edges of
+// literals merged by Compact reach no node there.
+//
+// Lookups by a path prefix, like fallbacks, apply to all these nodes as they
+// are keyed by the elements of the path rather than by a node.
+func (n *Node) Canonical(pathElements []string) []*Node {
+	var nodes []*Node
+	seen := make(map[*Node]bool)
+	n.reach(pathElements, func(node *Node) {
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	})
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].priority() < nodes[j].priority() })
+	return nodes
+}
+
+// Calls fn for every node reached by the elements from this node.
+func (n *Node) reach(elements []string, fn func(*Node)) {
+	if len(elements) == 0 {
+		fn(n)
+		return
+	}
+
+	for _, edge := range n.sortedEdges() {
+		switch {
+		case edge.maxspan > 0:
+			for count := edge.minspan; count <= edge.maxspan && count <= len(elements); count++ {
+				if n.conf.accepts(&edge.wildcards[0], strings.Join(elements[:count], n.conf.sep)) {
+					edge.node.reach(elements[count:], fn)
+				}
+			}
+		case edge.literals != nil:
+			if len(elements) >= len(edge.literals) && edge.matchLiterals(elements) {
+				edge.node.reach(elements[len(edge.literals):], fn)
+			}
+		default:
+			if _, ok := edge.match(elements[0], n.conf, false); ok {
+				edge.node.reach(elements[1:], fn)
+			}
+		}
+	}
+}
+
+// Reports if the elements start with the literals of a compacted edge, or are
+// the first of them.
+func (e *Edge) matchLiterals(elements []string) bool {
+	for i, el := range elements[:min(len(elements), len(e.literals))] {
+		if !e.parent.conf.equal(e.literals[i], el) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the order of the first pattern added through the node.
+func (n *Node) priority() int {
+	if n.parent == nil {
+		return 0
+	}
+	return n.parent.minorder
+}
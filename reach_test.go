@@ -0,0 +1,46 @@
+package pathtree
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	n := New()
+
+	n.Add("/", 0)
+	n.Add("/path|road|street/to|through/nowhere", 1)
+	n.Add("/path/:i/nowhere", 2)
+	n.Add("/:id/to/nowhere", 3)
+	n.Add("/:a/:b", 4)
+	n.Add("/not/found", 5)
+	n.Add("/:[2,3]x/found/:y", 6)
+
+	nodes := n.Canonical([]string{"path", "to"})
+	if len(nodes) != 4 {
+		t.Fatalf("Canonical (actual) %d nodes != 4 (expected)", len(nodes))
+	}
+	for i, pattern := range []string{"/path|road|street/to|through/nowhere", "/path/:i/nowhere", "/:id/to/nowhere"} {
+		if leaf := n.Get(pattern); nodes[i] != leaf.parent.parent.parent {
+			t.Errorf("Canonical node %d is not the one of %s", i, pattern)
+		}
+	}
+	if nodes[3].leaf == nil || nodes[3].leaf.Value != 4 {
+		t.Errorf("Canonical node 3 is not the one of /:a/:b")
+	}
+
+	// Find prefers the first node's patterns
+	found(t, n, "/path/to/nowhere", nil, 1)
+
+	if nodes := n.Canonical([]string{"not"}); len(nodes) != 4 || nodes[0] != n.edges[":id"].node {
+		t.Errorf("Canonical(not) (actual) %d nodes", len(nodes))
+	}
+	if nodes := n.Canonical([]string{"abcd"}); len(nodes) != 2 {
+		t.Errorf("Canonical should respect wildcard constraints (actual) %d nodes", len(nodes))
+	}
+	if nodes := n.Canonical(nil); len(nodes) != 1 || nodes[0] != n {
+		t.Errorf("Canonical of no elements should be the node itself")
+	}
+
+	n.Compact()
+	if nodes := n.Canonical([]string{"not", "found"}); len(nodes) != 3 {
+		t.Errorf("Canonical after Compact (actual) %d nodes", len(nodes))
+	}
+}
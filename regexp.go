@@ -0,0 +1,167 @@
+package pathtree
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Regexp compiles the pattern of this leaf into an anchored regular expression
+// with a named capture group per wildcard, for matching outside of Go.
+//
+// Length constraints become {min,max} quantifiers, enumerated values become
+// alternatives, stars become (?<name>.+?), padding is quoted and '|'
+// alternatives become non-capturing groups. Wildcards followed by padding are
+// lazy, mirroring Find taking the first occurrence of the padding. A trailing
+// slash is optional as with Find, and the base path, separator, dot segments
+// and case sensitivity of the tree are respected. Note that the regexp
+// measures lengths in characters where Find measures bytes, and that trees
+// compared with WithSegmentEqual or with bounds on element lengths have no
+// regexp.
+func (l *Leaf) Regexp() (*regexp.Regexp, error) {
+	expr, err := l.regexp()
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(expr)
+}
+
+// ExportRegexps returns the regular expression for every leaf in the tree,
+// keyed by pattern. Leafs with wildcard names that can't be used as capture
+// group names are left out.
+func (n *Node) ExportRegexps() map[string]string {
+	exprs := make(map[string]string)
+	n.walk(func(l *Leaf) {
+		if expr, err := l.regexp(); err == nil {
+			exprs[l.Pattern()] = expr
+		}
+	})
+	return exprs
+}
+
+func (l *Leaf) regexp() (string, error) {
+	if l.parent == nil {
+		return "", errors.New("leaf is not part of a tree")
+	}
+
+	root := l.root()
+	if root.conf.segeq != nil {
+		return "", errors.New("segment comparison function can't be expressed as a regexp")
+	}
+	base, sep := regexp.QuoteMeta(root.base), regexp.QuoteMeta(root.conf.sep)
+	expr := "^"
+	if root.conf.fold {
+		expr = "(?i)^"
+	}
+
+	edges := l.edges()
+	if len(edges) == 0 && !l.isStar() {
+		if base != "" {
+			return expr + base + sep + "?$", nil
+		}
+		return expr + sep + "$", nil
+	}
+
+	class := "[^" + sep + "]"
+	if root.conf.dots {
+		class = "[^" + sep + ".]"
+	}
+
+	expr += base
+	for _, edge := range edges {
+		expr += sep
+		if edge.maxlen > 0 {
+			return "", errors.New("element length bound " + formatMaxLength(edge.maxlen) + " can't be expressed as a regexp")
+		}
+		if edge.maxspan > 0 {
+			group, err := regexpSpan(edge, class, sep)
+			if err != nil {
+				return "", err
+			}
+			expr += group
+			continue
+		}
+		for key, value := range edge.wildcards {
+			group, err := regexpGroup(value, class, edge.wildend && key == len(edge.wildcards)-1)
+			if err != nil {
+				return "", err
+			}
+			expr += regexpPadding(edge.padding[key]) + group
+		}
+		if !edge.wildend {
+			expr += regexpPadding(edge.padding[len(edge.padding)-1])
+		}
+	}
+
+	if l.isStar() {
+		group, err := regexpGroup(Wildcard{Name: l.Wildcards[len(l.Wildcards)-1].Name, Min: 1}, ".", false)
+		if err != nil {
+			return "", err
+		}
+		expr += sep + group
+	}
+	return expr + sep + "?$", nil
+}
+
+var regexpName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func regexpGroup(wildcard Wildcard, class string, greedy bool) (string, error) {
+	if !regexpName.MatchString(wildcard.Name) {
+		return "", errors.New("wildcard name " + strconv.Quote(wildcard.Name) + " is not a valid capture group name")
+	}
+
+	if wildcard.Enum != nil {
+		return "(?<" + wildcard.Name + ">" + regexpAlternatives(wildcard.Enum) + ")", nil
+	}
+
+	if wildcard.Numeric {
+		class = "[0-9]"
+	}
+	var quantifier string
+	switch {
+	case wildcard.Max != 0 && wildcard.Min == wildcard.Max:
+		quantifier = "{" + strconv.Itoa(wildcard.Min) + "}"
+	case wildcard.Max != 0:
+		quantifier = "{" + strconv.Itoa(wildcard.Min) + "," + strconv.Itoa(wildcard.Max) + "}"
+	case wildcard.Min == 1:
+		quantifier = "+"
+	case wildcard.Min != 0:
+		quantifier = "{" + strconv.Itoa(wildcard.Min) + ",}"
+	default:
+		quantifier = "*"
+	}
+	if !greedy && wildcard.Max != wildcard.Min {
+		quantifier += "?"
+	}
+
+	return "(?<" + wildcard.Name + ">" + class + quantifier + ")", nil
+}
+
+func regexpSpan(edge *Edge, class, sep string) (string, error) {
+	name := edge.wildcards[0].Name
+	if !regexpName.MatchString(name) {
+		return "", errors.New("wildcard name " + strconv.Quote(name) + " is not a valid capture group name")
+	}
+	// Lazy, as Find tries the fewest path elements first
+	quantifier := "{" + strconv.Itoa(edge.minspan-1) + "," + strconv.Itoa(edge.maxspan-1) + "}"
+	if edge.minspan != edge.maxspan {
+		quantifier += "?"
+	}
+	return "(?<" + name + ">" + class + "*(?:" + sep + class + "*)" + quantifier + ")", nil
+}
+
+func regexpPadding(pads []string) string {
+	if len(pads) == 1 {
+		return regexp.QuoteMeta(pads[0])
+	}
+	return "(?:" + regexpAlternatives(pads) + ")"
+}
+
+func regexpAlternatives(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return strings.Join(quoted, "|")
+}
@@ -0,0 +1,35 @@
+package pathtree
+
+import (
+	"testing"
+)
+
+func TestExportRegexps(t *testing.T) {
+	n := New()
+
+	n.Add("/", 1)
+	n.Add("/path|road/:[3]id;.json", 2)
+	n.Add("/files/*path", 3)
+	n.Add("/bad/:my-var", 4)
+
+	exprs := n.ExportRegexps()
+	expected := map[string]string{
+		"/":                       "^/$",
+		"/path|road/:[3]id;.json": `^/(?:path|road)/(?<id>[^/]{3})\.json/?$`,
+		"/files/*path":            "^/files/(?<path>.+?)/?$",
+	}
+
+	if len(exprs) != len(expected) {
+		t.Errorf("Exported (actual) %v != %v (expected)", exprs, expected)
+	}
+	for pattern, expr := range expected {
+		if exprs[pattern] != expr {
+			t.Errorf("%s: Regexp (actual) %s != %s (expected)", pattern, exprs[pattern], expr)
+		}
+	}
+
+	leaf, _ := n.Find("/bad/x")
+	if _, err := leaf.Regexp(); err == nil {
+		t.Errorf("Expected an error for an invalid capture group name")
+	}
+}
@@ -0,0 +1,141 @@
+package pathtree
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ReverseAll reverses each of the leafs like Reverse, returning the paths and
+// the missing wildcards of each leaf in the same order. Every leaf may use
+// every variable, and vars is left unchanged.
+func (n *Node) ReverseAll(leaves []*Leaf, vars map[string]string) (paths []string, missing [][]string) {
+	paths = make([]string, len(leaves))
+	missing = make([][]string, len(leaves))
+	for i, leaf := range leaves {
+		paths[i], _, missing[i] = n.reverseWith(leaf, vars, false, nil)
+	}
+	return paths, missing
+}
+
+// ReverseWithQuery reverses the leaf like Reverse, appending the variables not
+// used by its wildcards as a query string sorted by name, eg.
+// "/users/7?page=2&sort=name". vars is left unchanged. Returns an error listing
+// the missing wildcards if there are any.
+func (n *Node) ReverseWithQuery(leaf *Leaf, vars map[string]string) (string, error) {
+	if leaf == nil || leaf.parent == nil {
+		return "", errors.New("leaf is not part of a tree")
+	}
+
+	variables := make(map[string]string, len(vars))
+	for name, value := range vars {
+		variables[name] = value
+	}
+	path, unused, missing := n.Reverse(leaf, variables)
+	if len(missing) > 0 {
+		return "", errors.New("missing wildcards " + strings.Join(missing, ", "))
+	}
+
+	if len(unused) > 0 {
+		query := make(url.Values, len(unused))
+		for name, value := range unused {
+			query.Set(name, value)
+		}
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
+
+// ReverseEncoded reverses the leaf like Reverse, percent-encoding the values of
+// its wildcards with url.PathEscape so the path is a valid URL, eg. "/files/a%20b"
+// for "a b". Padding and literals are kept as they are, and stars are encoded
+// element by element. Bounds apply to the values before encoding. Reverse
+// substitutes the values as they are. vars is left unchanged. Returns an error
+// listing the missing wildcards if there are any.
+func (n *Node) ReverseEncoded(leaf *Leaf, vars map[string]string) (string, error) {
+	if leaf == nil || leaf.parent == nil {
+		return "", errors.New("leaf is not part of a tree")
+	}
+
+	variables := make(map[string]string, len(vars))
+	for name, value := range vars {
+		variables[name] = value
+	}
+	path, _, missing := n.reverseWith(leaf, variables, true, url.PathEscape)
+	if len(missing) > 0 {
+		return "", errors.New("missing wildcards " + strings.Join(missing, ", "))
+	}
+	return path, nil
+}
+
+// ReverseMatches reverses the leaf like Reverse and reports if Find gives the
+// leaf back for the path, or the leaf it's an alias of, eg. to check in tests
+// that a path built from vars isn't shadowed by another pattern. It's false if
+// wildcards are missing. vars is left unchanged.
+func (n *Node) ReverseMatches(leaf *Leaf, vars map[string]string) (path string, ok bool) {
+	variables := make(map[string]string, len(vars))
+	for name, value := range vars {
+		variables[name] = value
+	}
+	path, _, missing := n.Reverse(leaf, variables)
+	if len(missing) > 0 {
+		return path, false
+	}
+
+	found, _ := n.Find(path)
+	return path, found != nil && (found == leaf || found == leaf.alias)
+}
+
+// Rewrite finds the incoming path like Find and reverses the target leaf with
+// the expansions of the leaf found by wildcard name, eg. to redirect
+// "/users/:id" to "/people/:id". Returns false if nothing was found or the
+// target has wildcards the leaf found doesn't.
+func (n *Node) Rewrite(incoming string, target *Leaf) (string, bool) {
+	if target == nil || target.parent == nil {
+		return "", false
+	}
+	m := n.Match(incoming)
+	if m == nil {
+		return "", false
+	}
+	path, _, missing := n.Reverse(target, m.Params())
+	return path, len(missing) == 0
+}
+
+// ReverseResult is a path built by Leaf.ReverseInfo, with what it was built
+// from.
+type ReverseResult struct {
+	Path     string            // the path, as Reverse returns it
+	Pattern  string            // the pattern of the leaf, see Leaf.Pattern
+	Source   string            // the pattern the leaf was added with, see Leaf.Source
+	Order    int               // the order the leaf was added in
+	Used     map[string]string // the variables substituted for wildcards
+	Unused   map[string]string // the other variables
+	Warnings []string          // the wildcards missing or out of bounds, as Reverse returns them
+}
+
+// ReverseInfo reverses the leaf like Reverse on the root of its tree, also
+// returning what the path was built from, eg. to trace generated links back to
+// their routes. vars is left unchanged. Returns an error listing the warnings
+// along with the result if wildcards are missing.
+func (l *Leaf) ReverseInfo(vars map[string]string) (ReverseResult, error) {
+	if l == nil || l.parent == nil {
+		return ReverseResult{}, errors.New("leaf is not part of a tree")
+	}
+
+	variables := make(map[string]string, len(vars))
+	for name, value := range vars {
+		variables[name] = value
+	}
+	path, unused, missing := l.root().Reverse(l, variables)
+	result := ReverseResult{Path: path, Pattern: l.Pattern(), Source: l.Source(), Order: l.order, Used: make(map[string]string), Unused: unused, Warnings: missing}
+	for name, value := range vars {
+		if _, ok := unused[name]; !ok {
+			result.Used[name] = value
+		}
+	}
+	if len(missing) > 0 {
+		return result, errors.New("missing wildcards " + strings.Join(missing, ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,178 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReverseAll(t *testing.T) {
+	n := New()
+
+	l1, _ := n.Add("/users/:user/", 1)
+	l2, _ := n.Add("/users/:user/posts/:[1,4]post", 2)
+	l3, _ := n.Add("/files/*path", 3)
+
+	vars := map[string]string{"user": "ann", "post": "12345"}
+	paths, missing := n.ReverseAll([]*Leaf{l1, l2, l3, nil}, vars)
+
+	expected := []string{"/users/ann/", "/users/ann/posts/", "/files", ""}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Paths (actual) %v != %v (expected)", paths, expected)
+	}
+	expectedMissing := [][]string{nil, {"[1,4]post"}, {"[0,0]path"}, {}}
+	if !reflect.DeepEqual(missing, expectedMissing) {
+		t.Errorf("Missing (actual) %v != %v (expected)", missing, expectedMissing)
+	}
+	if len(vars) != 2 {
+		t.Errorf("Variables were modified: %v", vars)
+	}
+
+	// Stars keep their trailing slash and extensions are appended as by Reverse
+	n = New(WithStarSlash(), WithExtractExtension())
+	l1, _ = n.Add("/dir/*path/", 1)
+	l2, _ = n.Add("/docs/:page", 2)
+	vars = map[string]string{"path": "a/", "page": "intro", "ext": "html"}
+	paths, _ = n.ReverseAll([]*Leaf{l1, l2}, vars)
+	for i, leaf := range []*Leaf{l1, l2} {
+		if path, _, _ := n.Reverse(leaf, map[string]string{"path": "a/", "page": "intro", "ext": "html"}); paths[i] != path {
+			t.Errorf("%s: (actual) %s != %s (Reverse)", leaf.Pattern(), paths[i], path)
+		}
+	}
+	if expected := []string{"/dir/a/", "/docs/intro.html"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Paths (actual) %v != %v (expected)", paths, expected)
+	}
+	if len(vars) != 3 {
+		t.Errorf("Variables were modified: %v", vars)
+	}
+}
+
+func TestReverseWithQuery(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/users/:id", 1)
+	l2, _ := n.Add("/files/*path", 2)
+
+	vars := map[string]string{"id": "7", "sort": "name", "q": "a&b c"}
+	if path, err := n.ReverseWithQuery(l1, vars); err != nil || path != "/users/7?q=a%26b+c&sort=name" {
+		t.Errorf("ReverseWithQuery (actual) %q %v", path, err)
+	}
+	if len(vars) != 3 {
+		t.Errorf("ReverseWithQuery changed the variables: %v", vars)
+	}
+	if path, err := n.ReverseWithQuery(l2, map[string]string{"path": "a/b"}); err != nil || path != "/files/a/b" {
+		t.Errorf("ReverseWithQuery without leftovers (actual) %q %v", path, err)
+	}
+	if _, err := n.ReverseWithQuery(l1, map[string]string{"sort": "name"}); err == nil || err.Error() != "missing wildcards [0,0]id" {
+		t.Errorf("Expected an error for a missing wildcard, got %v", err)
+	}
+	if _, err := n.ReverseWithQuery(nil, vars); err == nil {
+		t.Errorf("Expected an error for a nil leaf")
+	}
+}
+
+func TestReverseEncoded(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/users/:[1,3]id;_x y/:name", 1)
+	l2, _ := n.Add("/files/*path", 2)
+
+	vars := map[string]string{"id": "a b", "name": "x/y?z"}
+	if path, err := n.ReverseEncoded(l1, vars); err != nil || path != "/users/a%20b_x y/x%2Fy%3Fz" {
+		t.Errorf("ReverseEncoded (actual) %q %v", path, err)
+	}
+	if len(vars) != 2 || vars["id"] != "a b" {
+		t.Errorf("ReverseEncoded changed the variables: %v", vars)
+	}
+	if path, _, _ := n.Reverse(l1, map[string]string{"id": "a b", "name": "x/y?z"}); path != "/users/a b_x y/x/y?z" {
+		t.Errorf("Reverse (actual) %q", path)
+	}
+	if path, err := n.ReverseEncoded(l2, map[string]string{"path": "a b/c?d"}); err != nil || path != "/files/a%20b/c%3Fd" {
+		t.Errorf("ReverseEncoded of a star (actual) %q %v", path, err)
+	}
+	if _, err := n.ReverseEncoded(l1, map[string]string{"id": "abcd", "name": "x"}); err == nil || err.Error() != "missing wildcards [1,3]id" {
+		t.Errorf("Expected an error for an out of bounds wildcard, got %v", err)
+	}
+	if _, err := n.ReverseEncoded(nil, vars); err == nil {
+		t.Errorf("Expected an error for a nil leaf")
+	}
+}
+
+func TestReverseMatches(t *testing.T) {
+	n := New()
+	n.Add("/users/new", 1)
+	l2, _ := n.Add("/users/:id", 2)
+	l3, _ := n.Alias("/users/:id", "/u/:id")
+
+	vars := map[string]string{"id": "7"}
+	if path, ok := n.ReverseMatches(l2, vars); !ok || path != "/users/7" {
+		t.Errorf("ReverseMatches (actual) %s %v", path, ok)
+	}
+	if len(vars) != 1 {
+		t.Errorf("ReverseMatches changed the variables: %v", vars)
+	}
+	if path, ok := n.ReverseMatches(l2, map[string]string{"id": "new"}); ok || path != "/users/new" {
+		t.Errorf("ReverseMatches of a shadowed path (actual) %s %v", path, ok)
+	}
+	if path, ok := n.ReverseMatches(l3, vars); !ok || path != "/u/7" {
+		t.Errorf("ReverseMatches of an alias (actual) %s %v", path, ok)
+	}
+	if _, ok := n.ReverseMatches(l2, nil); ok {
+		t.Errorf("ReverseMatches with a missing wildcard should fail")
+	}
+	if _, ok := n.ReverseMatches(nil, vars); ok {
+		t.Errorf("ReverseMatches of a nil leaf should fail")
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	n := New()
+	n.Add("/users/:id/posts/:post", 1)
+	n.Add("/files/*path", 2)
+	target, _ := n.Add("/people/:id/p/:post", 3)
+	static, _ := n.Add("/static/*path", 4)
+
+	if path, ok := n.Rewrite("/users/7/posts/9", target); !ok || path != "/people/7/p/9" {
+		t.Errorf("Rewrite (actual) %s %v", path, ok)
+	}
+	if path, ok := n.Rewrite("/files/css/site.css", static); !ok || path != "/static/css/site.css" {
+		t.Errorf("Rewrite of a star (actual) %s %v", path, ok)
+	}
+	if _, ok := n.Rewrite("/files/css/site.css", target); ok {
+		t.Errorf("Rewrite with missing wildcards should fail")
+	}
+	if _, ok := n.Rewrite("/missing", target); ok {
+		t.Errorf("Rewrite of a path not found should fail")
+	}
+	if _, ok := n.Rewrite("/users/7/posts/9", nil); ok {
+		t.Errorf("Rewrite to a nil leaf should fail")
+	}
+}
+
+func TestReverseInfo(t *testing.T) {
+	n := New().WithBasePath("/api")
+	n.Add("/", 0)
+	leaf, _ := n.Add("/users/:id;/:[1,4]tab", 1)
+
+	vars := map[string]string{"id": "7", "tab": "feed", "page": "2"}
+	result, err := leaf.ReverseInfo(vars)
+	expected := ReverseResult{
+		Path:    "/api/users/7/feed",
+		Pattern: "/users/:id/:[1,4]tab",
+		Source:  "/users/:id;/:[1,4]tab",
+		Order:   2,
+		Used:    map[string]string{"id": "7", "tab": "feed"},
+		Unused:  map[string]string{"page": "2"},
+	}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("ReverseInfo (actual) %+v %v != %+v (expected)", result, err, expected)
+	}
+	if len(vars) != 3 {
+		t.Errorf("ReverseInfo changed the variables: %v", vars)
+	}
+
+	result, err = leaf.ReverseInfo(map[string]string{"id": "7", "tab": "history"})
+	if err == nil || result.Path != "/api/users/7/" || !reflect.DeepEqual(result.Warnings, []string{"[1,4]tab"}) || len(result.Used) != 1 {
+		t.Errorf("ReverseInfo out of bounds (actual) %+v %v", result, err)
+	}
+	if _, err := (*Leaf)(nil).ReverseInfo(vars); err == nil {
+		t.Errorf("Expected an error for a nil leaf")
+	}
+}
@@ -0,0 +1,72 @@
+package pathtree
+
+import "sync/atomic"
+
+// FrozenTree is a read-only copy of a tree, safe for concurrent lookups.
+type FrozenTree struct {
+	tree *Node
+}
+
+// Snapshot returns a read-only copy of the tree, see Clone.
+func (n *Node) Snapshot() *FrozenTree {
+	return &FrozenTree{n.Clone()}
+}
+
+// Find finds a given path, see Node.Find.
+func (f *FrozenTree) Find(key string) (leaf *Leaf, expansions []string) {
+	return f.tree.Find(key)
+}
+
+// Get returns the leaf added with the given pattern, see Node.Get.
+func (f *FrozenTree) Get(pattern string) *Leaf {
+	return f.tree.Get(pattern)
+}
+
+// Walk calls fn for every leaf of the tree, see Node.Walk.
+func (f *FrozenTree) Walk(fn func(leaf *Leaf)) {
+	f.tree.Walk(fn)
+}
+
+// MarshalJSON encodes the routes of the tree, see Node.MarshalJSON.
+func (f *FrozenTree) MarshalJSON() ([]byte, error) {
+	return f.tree.MarshalJSON()
+}
+
+// Reverse generates the path of a leaf, see Node.Reverse.
+func (f *FrozenTree) Reverse(leaf *Leaf, variables map[string]string) (path string, unused map[string]string, err []string) {
+	return f.tree.Reverse(leaf, variables)
+}
+
+// Router publishes trees for lookups without locking. Each Reload builds a new
+// tree and swaps it in atomically, so lookups see either the old tree or the
+// new one, and never one being built.
+type Router struct {
+	opts    []Option
+	current atomic.Pointer[FrozenTree]
+}
+
+// NewRouter returns a router whose trees are created with the options, and
+// which finds nothing until the first Reload.
+func NewRouter(opts ...Option) *Router {
+	return &Router{opts: opts}
+}
+
+// Reload builds a new tree with build and publishes it.
+func (r *Router) Reload(build func(*Node)) {
+	n := New(r.opts...)
+	build(n)
+	r.current.Store(&FrozenTree{n})
+}
+
+// Load returns the tree last published, or nil if there is none.
+func (r *Router) Load() *FrozenTree {
+	return r.current.Load()
+}
+
+// Find finds a given path in the tree last published.
+func (r *Router) Find(key string) (leaf *Leaf, expansions []string) {
+	if f := r.current.Load(); f != nil {
+		return f.Find(key)
+	}
+	return nil, nil
+}
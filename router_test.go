@@ -0,0 +1,58 @@
+package pathtree
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	n := New()
+	n.Add("/a/:b", 1)
+
+	f := n.Snapshot()
+	n.Add("/c", 2)
+	n.Update("/a/:b", 3)
+
+	if leaf, exp := f.Find("/a/x"); leaf == nil || leaf.Value != 1 || exp[0] != "x" {
+		t.Errorf("Snapshot Find (actual) %v %v", leaf, exp)
+	}
+	if leaf, _ := f.Find("/c"); leaf != nil {
+		t.Errorf("Snapshot should not see later changes")
+	}
+	if path, _, _ := f.Reverse(f.Get("/a/:b"), map[string]string{"b": "y"}); path != "/a/y" {
+		t.Errorf("Snapshot Reverse (actual) %s", path)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter(WithCaseInsensitive())
+	if leaf, _ := r.Find("/a"); leaf != nil || r.Load() != nil {
+		t.Errorf("Router should find nothing before the first Reload")
+	}
+
+	r.Reload(func(n *Node) { n.Add("/version/:v", 0) })
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			r.Reload(func(n *Node) { n.Add(fmt.Sprintf("/version/:v%d", i), i) })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if leaf, _ := r.Find("/VERSION/x"); leaf == nil {
+				t.Errorf("Router found nothing during reloads")
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if leaf, _ := r.Find("/version/x"); leaf == nil || leaf.Value != 100 {
+		t.Errorf("Router Find after reloads (actual) %v", leaf)
+	}
+}
@@ -0,0 +1,30 @@
+package pathtree
+
+// PatternSchema is the pattern of a leaf with the constraints of its
+// wildcards, as returned by Schema.
+type PatternSchema struct {
+	Pattern string     `json:"pattern"`
+	Vars    []Wildcard `json:"vars"`
+}
+
+// Schema returns every pattern in the tree with its wildcards, including their
+// bounds and enumerated values, in the order of Walk, eg. for clients to
+// validate paths before requesting them. Patterns with several leafs, like
+// versions, are only listed once.
+func (n *Node) Schema() []PatternSchema {
+	var schema []PatternSchema
+	seen := make(map[string]bool)
+	n.Walk(func(leaf *Leaf) {
+		pattern := leaf.Pattern()
+		if seen[pattern] {
+			return
+		}
+		seen[pattern] = true
+		vars := leaf.Wildcards
+		if vars == nil {
+			vars = []Wildcard{}
+		}
+		schema = append(schema, PatternSchema{Pattern: pattern, Vars: vars})
+	})
+	return schema
+}
@@ -0,0 +1,28 @@
+package pathtree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	n := New()
+	n.DefineWildcard("id", Wildcard{Min: 1, Max: 8})
+	n.Add("/", 0)
+	n.Add("/users/:@id", 1)
+	n.Add("/files/:[2,4]kind(gif|png)/*path", 2)
+	n.AddVersioned("/v/:x", "a", 1, 1)
+	n.AddVersioned("/v/:x", "b", 2, 2)
+
+	data, err := json.Marshal(n.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"pattern":"/","vars":[]},` +
+		`{"pattern":"/files/:[2,4]kind(gif|png)/*path","vars":[{"Name":"kind","Min":2,"Max":4,"Enum":["gif","png"]},{"Name":"path","Min":0,"Max":0,"Enum":null}]},` +
+		`{"pattern":"/users/:@id","vars":[{"Name":"id","Min":1,"Max":8,"Enum":null}]},` +
+		`{"pattern":"/v/:x","vars":[{"Name":"x","Min":0,"Max":0,"Enum":null}]}]`
+	if string(data) != expected {
+		t.Errorf("Schema (actual) %s != %s (expected)", data, expected)
+	}
+}
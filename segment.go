@@ -0,0 +1,137 @@
+package pathtree
+
+import (
+	"errors"
+	"strings"
+)
+
+// Segment is a compiled path element pattern, with the syntax of a single
+// element of the patterns added to a tree, eg. "Archive_:first;_:[2,4]year".
+type Segment struct {
+	padding   [][]string // possible padding elements between each var
+	wildcards []Wildcard // wildcard elements being the vars
+	wildend   bool       // if it ends with a wildcard
+	maxlen    int        // maximum length of the whole element (0 for none)
+}
+
+// CompileSegment parses a path element pattern for matching with Match. It may
+// start with a bound on its total length, eg. "<=64>:a;-:b". Like Add it
+// returns a *SyntaxError for malformed bounds or bounds that keep the element
+// from ever matching.
+func CompileSegment(pattern string) (*Segment, error) {
+	if strings.Contains(pattern, "/") {
+		return nil, errors.New("segment can't contain /")
+	}
+	if len(pattern) > 0 && pattern[0] == '*' {
+		return nil, errors.New("segment can't be a star")
+	}
+	if pos, msg := checkBounds(pattern); msg != "" {
+		return nil, &SyntaxError{Pattern: pattern, Element: 1, Offset: pos, Msg: msg}
+	}
+	if msg := (&config{sep: "/"}).satisfiable(pattern); msg != "" {
+		return nil, &SyntaxError{Pattern: pattern, Element: 1, Msg: msg}
+	}
+	if err := checkMaxLength([]string{pattern}); err != nil {
+		return nil, err
+	}
+	if len(pattern) > 0 && pattern[len(pattern)-1] == ';' {
+		pattern = pattern[:len(pattern)-1]
+	}
+	segment := newSegment(pattern)
+	return &segment, nil
+}
+
+// MatchSegment compiles a path element pattern and matches input against it.
+func MatchSegment(pattern, input string) (vars []string, ok bool, err error) {
+	segment, err := CompileSegment(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+	vars, ok = segment.Match(input)
+	return vars, ok, nil
+}
+
+// Match matches a single path element against the segment as Find would with
+// the default options, returning the wildcard expansions in order.
+func (s *Segment) Match(input string) (vars []string, ok bool) {
+	return s.match(input, &config{sep: "/"}, false)
+}
+
+// Wildcards returns the wildcards of the segment, in order they appear.
+func (s *Segment) Wildcards() []Wildcard {
+	return s.wildcards
+}
+
+func newSegment(el string) Segment {
+	el, maxlen := cutMaxLength(el)
+	paddings, variables, wildend := parseElement(el)
+	return Segment{padding: paddings, wildcards: variables, wildend: wildend, maxlen: maxlen}
+}
+
+// Matches input against the segment, ignoring the constraints of its wildcards
+// if relaxed.
+func (s *Segment) match(input string, c *config, relaxed bool) (vars []string, ok bool) {
+	var buf [4]Span
+	spans, ok := s.bounds(input, c, relaxed, buf[:0])
+	if !ok {
+		return nil, false
+	}
+	vars = make([]string, len(spans))
+	for i, span := range spans {
+		vars[i] = input[span.Start:span.End]
+	}
+	return vars, true
+}
+
+// Matches input like match, appending the byte ranges of the wildcard
+// expansions in input to spans.
+func (s *Segment) bounds(input string, c *config, relaxed bool, spans []Span) ([]Span, bool) {
+	if s.maxlen > 0 && c.length(input) > s.maxlen {
+		return nil, false
+	}
+
+	accepts := c.accepts
+	if relaxed {
+		accepts = c.allows
+	}
+
+	// Check all padding elements are present and exit at first failure. The
+	// padding before the first wildcard anchors it to the start of the input,
+	// the one after the last wildcard to the end.
+	offset := 0
+	for count, pads := range s.padding {
+		found := false
+		rest := input[offset:]
+		for _, pad := range pads {
+			pos, end := c.index(rest, pad)
+			if !s.wildend && count == len(s.padding)-1 {
+				pos, end = c.suffix(rest, pad), len(rest)
+			}
+			if (pos == -1) || (count == 0 && pos > 0) {
+				continue
+			}
+
+			if count != 0 {
+				if !accepts(&s.wildcards[count-1], rest[:pos]) {
+					continue
+				}
+				spans = append(spans, Span{offset, offset + pos})
+			}
+			offset += end
+			found = true
+			break
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	if s.wildend {
+		if !accepts(&s.wildcards[len(s.wildcards)-1], input[offset:]) {
+			return nil, false
+		}
+		spans = append(spans, Span{offset, len(input)})
+	}
+	return spans, true
+}
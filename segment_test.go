@@ -0,0 +1,74 @@
+package pathtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchSegment(t *testing.T) {
+	cases := []struct {
+		pattern, input string
+		vars           []string
+		ok             bool
+	}{
+		{"Archive_:first;_:[2,4]year;", "Archive_March_2013", []string{"March", "2013"}, true},
+		{"Archive_:first;_:[2,4]year;", "Archive_March_1", nil, false},
+		{"Archive_|History_:first;_all", "History_May_all", []string{"May"}, true},
+		{":v(x|y)", "x", []string{"x"}, true},
+		{":v(x|y)", "z", nil, false},
+		{"static", "static", []string{}, true},
+		{"static", "dynamic", nil, false},
+	}
+	for _, c := range cases {
+		vars, ok, err := MatchSegment(c.pattern, c.input)
+		if err != nil || ok != c.ok || !reflect.DeepEqual(vars, c.vars) {
+			t.Errorf("%s %s: (actual) %q %v %v != %q %v (expected)", c.pattern, c.input, vars, ok, err, c.vars, c.ok)
+		}
+	}
+
+	for _, pattern := range []string{"a/b", "*rest", "<=4>abc"} {
+		if _, _, err := MatchSegment(pattern, "x"); err == nil {
+			t.Errorf("%s: expected an error", pattern)
+		}
+	}
+}
+
+func TestCompileSegmentSyntaxError(t *testing.T) {
+	cases := []struct {
+		pattern string
+		offset  int
+		msg     string
+	}{
+		{":[2,x]year", 4, `invalid max bound "x"`},
+		{"a_:[10,2]x;", 4, "min bound 10 above max bound 2"},
+		{":[3", 1, "unclosed bound"},
+		{"<=6>id_:[5]x", 0, "element at least 8 long, above its length bound 6"},
+		{":[3]x(ab|cd)", 0, "no value of wildcard x within its bounds"},
+	}
+	for _, c := range cases {
+		_, err := CompileSegment(c.pattern)
+		var e *SyntaxError
+		if !errors.As(err, &e) || e.Pattern != c.pattern || e.Element != 1 || e.Offset != c.offset || e.Msg != c.msg {
+			t.Errorf("%s: (actual) %v != %d %s (expected)", c.pattern, err, c.offset, c.msg)
+		}
+		if _, ok, err := MatchSegment(c.pattern, "x"); ok || !errors.As(err, &e) {
+			t.Errorf("%s: MatchSegment (actual) %v %v", c.pattern, ok, err)
+		}
+	}
+}
+
+func TestCompileSegment(t *testing.T) {
+	segment, err := CompileSegment("img_:name;.jpg|.jpeg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wildcards := segment.Wildcards(); !reflect.DeepEqual(wildcards, []Wildcard{{Name: "name"}}) {
+		t.Errorf("Wildcards (actual) %v", wildcards)
+	}
+	for input, name := range map[string]string{"img_cat.jpg": "cat", "img_dog.jpeg": "dog"} {
+		if vars, ok := segment.Match(input); !ok || !reflect.DeepEqual(vars, []string{name}) {
+			t.Errorf("%s: (actual) %q %v", input, vars, ok)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package pathtree
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parses a multi-element wildcard, a path element of the form ":N{name}" or
+// ":N-M{name}" matching from N to M path elements.
+func parseSpan(el string) (min, max int, name string, ok bool) {
+	if len(el) < 4 || el[0] != ':' || el[len(el)-1] != '}' {
+		return 0, 0, "", false
+	}
+	open := strings.IndexByte(el, '{')
+	if open == -1 {
+		return 0, 0, "", false
+	}
+
+	count, name := el[1:open], el[open+1:len(el)-1]
+	lo, hi, ranged := strings.Cut(count, "-")
+	var err error
+	if min, err = strconv.Atoi(lo); err != nil || min < 1 {
+		return 0, 0, "", false
+	}
+	max = min
+	if ranged {
+		if max, err = strconv.Atoi(hi); err != nil || max < min {
+			return 0, 0, "", false
+		}
+	}
+	return min, max, name, true
+}
+
+// Returns the multi-element wildcard path element for the counts and name.
+func formatSpan(min, max int, name string) string {
+	count := strconv.Itoa(min)
+	if max != min {
+		count += "-" + strconv.Itoa(max)
+	}
+	return ":" + count + "{" + name + "}"
+}
+
+// Reports if a value for the wildcard of the edge has an allowed number of
+// path elements, which it always has unless the edge is a multi-element one.
+func (e *Edge) spans(value, sep string) bool {
+	if e.maxspan == 0 {
+		return true
+	}
+	count := strings.Count(value, sep) + 1
+	return e.minspan <= count && count <= e.maxspan
+}
+
+// Matches a multi-element wildcard edge against el and the elements after it.
+func (e *Edge) findSpan(el string, elements, exp []string, q *query, conf *config) (leaf *Leaf, expansions []string) {
+	rest := append([]string{el}, elements...)
+	for count := e.minspan; count <= e.maxspan && count <= len(rest); count++ {
+		value := strings.Join(rest[:count], conf.sep)
+		if !conf.accepts(&e.wildcards[0], value) {
+			continue
+		}
+		testleaf, testexpansions := e.node.find(rest[count:], append(exp[:len(exp):len(exp)], value), q)
+		if testleaf != nil && (leaf == nil || conf.ranks(testleaf, leaf)) {
+			leaf, expansions = testleaf, testexpansions
+		}
+	}
+	return leaf, expansions
+}
@@ -0,0 +1,41 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpan(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/proj/:2{path}/settings", 1)
+	n.Add("/docs/:1-3{path}/edit", 3)
+	l2, _ := n.Add("/docs/:1-3{path}", 2)
+
+	found(t, n, "/proj/a/b/settings", []string{"a/b"}, 1)
+	notfound(t, n, "/proj/a/settings")
+	notfound(t, n, "/proj/a/b/c/settings")
+	found(t, n, "/docs/a", []string{"a"}, 2)
+	found(t, n, "/docs/a/b/c", []string{"a/b/c"}, 2)
+	notfound(t, n, "/docs/a/b/c/d")
+	found(t, n, "/docs/a/b/edit", []string{"a/b"}, 3)
+
+	reverse(t, n, l1, map[string]string{"path": "org/repo"}, "/proj/org/repo/settings", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{"path": "org"}, "/proj//settings", map[string]string{"path": "org"}, []string{"[0,0]path"})
+	reverse(t, n, l2, map[string]string{"path": "a/b/c"}, "/docs/a/b/c", map[string]string{}, nil)
+
+	if params := l1.Params(); !reflect.DeepEqual(params, []ParamInfo{{Name: "path", Element: 1}}) {
+		t.Errorf("Params (actual) %+v", params)
+	}
+	if _, segments := n.FindAligned("/docs/a/b/edit"); !reflect.DeepEqual(segments, []SegmentMatch{
+		{"docs", "docs", false}, {":1-3{path}", "a/b", true}, {"edit", "edit", false},
+	}) {
+		t.Errorf("FindAligned (actual) %v", segments)
+	}
+
+	if canonical, _ := Canonicalize("/proj/:2-2{path}/"); canonical != "/proj/:2{path}/" {
+		t.Errorf("Canonicalize (actual) %q", canonical)
+	}
+	if shape := CanonicalShape("/proj/:2{path}/:id"); shape != "/proj/:2{0}/:1;" {
+		t.Errorf("CanonicalShape (actual) %q", shape)
+	}
+}
@@ -0,0 +1,118 @@
+package pathtree
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Suggest returns up to limit patterns completing prefix, eg. for "/Arch" the
+// patterns "/Archive_:first;_all" and "/Archive_:first;_:year". The complete
+// path elements of prefix are matched against the patterns like Find does or
+// compared with them, while the last one may be the start of an element, or
+// of the padding it starts with and a wildcard after it. Shorter patterns
+// come first, those added first among patterns as long. A limit of 0 returns
+// every pattern.
+func (n *Node) Suggest(prefix string, limit int) []string {
+	sep := n.conf.sep
+	if len(prefix) == 0 || prefix[0] != sep[0] {
+		return nil
+	}
+	elements := strings.Split(prefix[len(sep):], sep)
+
+	var nodes []*Node
+	var stars []*Leaf
+	n.complete(elements[:len(elements)-1], elements[len(elements)-1], &nodes, &stars)
+
+	// Go down the nodes completing the prefix a level at a time
+	var suggestions []string
+	level := stars
+	for (len(nodes) > 0 || len(level) > 0) && (limit == 0 || len(suggestions) < limit) {
+		var next []*Node
+		for _, node := range nodes {
+			if node.leaf != nil && !node.leaf.hidden {
+				level = append(level, node.leaf)
+			}
+			if node.star != nil && !node.star.hidden {
+				level = append(level, node.star)
+			}
+			for _, edge := range node.sortedEdges() {
+				next = append(next, edge.node)
+			}
+		}
+		sort.SliceStable(level, func(i, j int) bool { return level[i].order < level[j].order })
+		for _, leaf := range level {
+			if limit != 0 && len(suggestions) == limit {
+				break
+			}
+			suggestions = append(suggestions, leaf.Pattern())
+		}
+		nodes, level = next, nil
+	}
+	return suggestions
+}
+
+// Adds the nodes whose patterns complete the typed elements followed by the
+// start of an element, and the stars completing them.
+func (n *Node) complete(typed []string, partial string, nodes *[]*Node, stars *[]*Leaf) {
+	if len(typed) == 0 {
+		if partial == "" {
+			*nodes = append(*nodes, n)
+			return
+		}
+		for _, edge := range n.sortedEdges() {
+			if edge.completes(partial) {
+				*nodes = append(*nodes, edge.node)
+			}
+		}
+		if n.star != nil && !n.star.hidden && strings.HasPrefix(starElement(n.star.Wildcards[len(n.star.Wildcards)-1].Name), partial) {
+			*stars = append(*stars, n.star)
+		}
+		return
+	}
+
+	for _, edge := range n.sortedEdges() {
+		if edge.literals == nil {
+			if edge.repr == strings.TrimSuffix(typed[0], ";") || (edge.maxspan == 0 && edge.matches(typed[0], n.conf)) {
+				edge.node.complete(typed[1:], partial, nodes, stars)
+			}
+			continue
+		}
+
+		// Compacted edges may end after the prefix does
+		count := min(len(typed), len(edge.literals))
+		if !slices.Equal(edge.literals[:count], typed[:count]) {
+			continue
+		}
+		if count < len(edge.literals) {
+			if strings.HasPrefix(edge.literals[count], partial) {
+				*nodes = append(*nodes, edge.node)
+			}
+			continue
+		}
+		edge.node.complete(typed[count:], partial, nodes, stars)
+	}
+}
+
+// Reports if the edge matches a path element.
+func (e *Edge) matches(el string, c *config) bool {
+	_, ok := e.match(el, c, false)
+	return ok
+}
+
+// Reports if partial is the start of the pattern of the edge, or of a path
+// element it matches up to its first wildcard.
+func (e *Edge) completes(partial string) bool {
+	if e.literals != nil {
+		return strings.HasPrefix(e.literals[0], partial)
+	}
+	if strings.HasPrefix(e.repr, partial) {
+		return true
+	}
+	for _, pad := range e.padding[0] {
+		if strings.HasPrefix(pad, partial) || (len(e.wildcards) > 0 && strings.HasPrefix(partial, pad)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	n := New()
+	n.Add("/Archive_:first;_all", 1)
+	n.Add("/Archive_:first;_:year;", 2)
+	n.Add("/about", 3)
+	n.Add("/users/:id", 4)
+	n.Add("/users/:id/posts/:post", 5)
+	n.Add("/users/new", 6)
+	n.Add("/users/:id/profile", 7)
+	n.Add("/files/*path", 8)
+	n.Add("/docs/api/v1/intro", 9)
+	n.Add("/", 10)
+	n.Compact()
+
+	cases := []struct {
+		prefix   string
+		limit    int
+		expected []string
+	}{
+		{"/Arch", 0, []string{"/Archive_:first;_all", "/Archive_:first;_:year"}},
+		{"/Archive_20", 0, []string{"/Archive_:first;_all", "/Archive_:first;_:year"}},
+		{"/A", 1, []string{"/Archive_:first;_all"}},
+		{"/a", 0, []string{"/about"}},
+		{"/users/", 0, []string{"/users/:id", "/users/new", "/users/:id/profile", "/users/:id/posts/:post"}},
+		{"/users/", 2, []string{"/users/:id", "/users/new"}},
+		{"/users/7/p", 0, []string{"/users/:id/profile", "/users/:id/posts/:post"}},
+		{"/users/:id/po", 0, []string{"/users/:id/posts/:post"}},
+		{"/files/", 0, []string{"/files/*path"}},
+		{"/files/*", 0, []string{"/files/*path"}},
+		{"/docs/api/v", 0, []string{"/docs/api/v1/intro"}},
+		{"/docs/", 0, []string{"/docs/api/v1/intro"}},
+		{"/x", 0, nil},
+		{"users", 0, nil},
+	}
+	for _, c := range cases {
+		if suggestions := n.Suggest(c.prefix, c.limit); !reflect.DeepEqual(suggestions, c.expected) {
+			t.Errorf("%s: (actual) %q != %q (expected)", c.prefix, suggestions, c.expected)
+		}
+	}
+
+	if suggestions := n.Suggest("/", 3); !reflect.DeepEqual(suggestions, []string{"/", "/Archive_:first;_all", "/Archive_:first;_:year"}) {
+		t.Errorf("/: (actual) %q", suggestions)
+	}
+}
@@ -0,0 +1,140 @@
+package pathtree
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError is a malformed pattern, located down to the byte in the pattern
+// so editors can point at it.
+type SyntaxError struct {
+	Pattern string // the pattern
+	Element int    // the index of the path element, from 1
+	Offset  int    // the byte offset in Pattern
+	Msg     string // what's wrong
+}
+
+func (e *SyntaxError) Error() string {
+	return "pattern " + strconv.Quote(e.Pattern) + ": element " + strconv.Itoa(e.Element) + ", offset " + strconv.Itoa(e.Offset) + ": " + e.Msg
+}
+
+// Checks the bounds of the wildcards of a pattern starting with sep, returning
+// a *SyntaxError for the first malformed one.
+func checkSyntax(pattern, sep string) error {
+	offset := len(sep)
+	for i, el := range strings.Split(pattern[len(sep):], sep) {
+		if pos, msg := checkBounds(el); msg != "" {
+			return &SyntaxError{Pattern: pattern, Element: i + 1, Offset: offset + pos, Msg: msg}
+		}
+		offset += len(el) + len(sep)
+	}
+	return nil
+}
+
+// Checks the bounds of the wildcards of a parsed pattern can be met with
+// lengths counted as in this tree, returning a *SyntaxError for the first
+// element they keep from ever matching.
+func (c *config) checkSatisfiable(p *Pattern) error {
+	offset := len(c.sep)
+	raw := strings.Split(p.source[len(c.sep):], c.sep)
+	for i, el := range p.elements {
+		if msg := c.satisfiable(el); msg != "" {
+			return &SyntaxError{Pattern: p.source, Element: i + 1, Offset: offset, Msg: msg}
+		}
+		if i < len(raw) {
+			offset += len(raw[i]) + len(c.sep)
+		}
+	}
+	return nil
+}
+
+// Checks the bounds of the wildcards of a path element, like ":[2,4]year",
+// returning the offset in el of the first malformed one and what's wrong.
+func checkBounds(el string) (offset int, msg string) {
+	in := false
+	for i := 0; i < len(el); i++ {
+		switch {
+		case i == 0 && isNumeric(el) && el[1] == '[':
+			in = true
+			if pos, msg := checkBound(el[1:]); msg != "" {
+				return 1 + pos, msg
+			}
+		case in && el[i] == ';':
+			in = false
+		case !in && el[i] == ':':
+			in = true
+			if i+1 < len(el) && el[i+1] == '[' {
+				if pos, msg := checkBound(el[i+1:]); msg != "" {
+					return i + 1 + pos, msg
+				}
+			}
+		}
+	}
+	return 0, ""
+}
+
+// Checks the bound a wildcard starts with, returning the offset in w of what's
+// wrong with it.
+func checkBound(w string) (offset int, msg string) {
+	if end := strings.IndexByte(w, ';'); end >= 0 {
+		w = w[:end]
+	}
+	end := strings.IndexByte(w, ']')
+	if end == -1 {
+		return 0, "unclosed bound"
+	}
+	lo, hi, ranged := strings.Cut(w[1:end], ",")
+	low, err := strconv.Atoi(lo)
+	if err != nil && ranged {
+		return 1, "invalid min bound " + strconv.Quote(lo)
+	} else if err != nil {
+		return 1, "invalid bound " + strconv.Quote(lo)
+	}
+	high, err := strconv.Atoi(hi)
+	if ranged && err != nil {
+		return len(lo) + 2, "invalid max bound " + strconv.Quote(hi)
+	}
+	if ranged && high != 0 && low > high {
+		return 1, "min bound " + lo + " above max bound " + hi
+	}
+	if end+1 == len(w) {
+		return end + 1, "missing wildcard name"
+	}
+	return 0, ""
+}
+
+// Checks a path element with well formed bounds can match something, returning
+// why not if it can't.
+func (c *config) satisfiable(el string) string {
+	rest, maxlen := cutMaxLength(el)
+	rest = strings.TrimSuffix(rest, ";")
+	if _, _, _, span := parseSpan(rest); span || (len(rest) > 0 && rest[0] == '*') {
+		return ""
+	}
+
+	paddings, wildcards, _ := parseElement(rest)
+	for _, w := range wildcards {
+		if w.Enum != nil && !slices.ContainsFunc(w.Enum, func(v string) bool { return w.acceptsLength(v, c.length(v)) }) {
+			return "no value of wildcard " + w.Name + " within its bounds"
+		}
+	}
+	if maxlen == 0 {
+		return ""
+	}
+	minlen := 0
+	for _, pads := range paddings {
+		shortest := c.length(pads[0])
+		for _, pad := range pads[1:] {
+			shortest = min(shortest, c.length(pad))
+		}
+		minlen += shortest
+	}
+	for _, w := range wildcards {
+		minlen += w.Min
+	}
+	if minlen > maxlen {
+		return "element at least " + strconv.Itoa(minlen) + " long, above its length bound " + strconv.Itoa(maxlen)
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package pathtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSyntaxError(t *testing.T) {
+	n := New()
+
+	cases := []struct {
+		pattern string
+		element int
+		offset  int
+		msg     string
+	}{
+		{"/Archive_:first;_:[2,x]year;", 1, 21, `invalid max bound "x"`},
+		{"/a/:[y,4]b", 2, 5, `invalid min bound "y"`},
+		{"/a/b/c_:[z]d", 3, 9, `invalid bound "z"`},
+		{"/:[3", 1, 2, "unclosed bound"},
+		{"/x/:a;-:[3;", 2, 8, "unclosed bound"},
+		{"/x/y/:[1,2]", 3, 11, "missing wildcard name"},
+		{"/a/:[10,2]x;", 2, 5, "min bound 10 above max bound 2"},
+		{"/<=6>id_:[5]x", 1, 1, "element at least 8 long, above its length bound 6"},
+		{"/a/<=4>ab|cd_:[2,4]x;_:y", 2, 3, "element at least 5 long, above its length bound 4"},
+		{"/:[3]x(ab|cd)", 1, 1, "no value of wildcard x within its bounds"},
+	}
+	for _, c := range cases {
+		_, err := n.Add(c.pattern, 1)
+		var e *SyntaxError
+		if !errors.As(err, &e) {
+			t.Errorf("%s: Expected a syntax error (actual) %v", c.pattern, err)
+			continue
+		}
+		if e.Pattern != c.pattern || e.Element != c.element || e.Offset != c.offset || e.Msg != c.msg {
+			t.Errorf("%s: (actual) %d %d %s != %d %d %s (expected)", c.pattern, e.Element, e.Offset, e.Msg, c.element, c.offset, c.msg)
+		}
+	}
+
+	expected := `pattern "/Archive_:first;_:[2,x]year;": element 1, offset 21: invalid max bound "x"`
+	if _, err := n.Add("/Archive_:first;_:[2,x]year;", 1); err == nil || err.Error() != expected {
+		t.Errorf("Error (actual) %v != %s (expected)", err, expected)
+	}
+
+	for _, pattern := range []string{"/Archive_:first;_:[2,4]year;", "/:[8]date", "/a/:[2]a(ab|cd)/b:x[1]", "/:2{b}", "/:[2,0]c", "/<=8>id_:[5]x", "/<=5>ab|cd_:[2,4]x;_:y"} {
+		if _, err := n.Add(pattern, 1); err != nil {
+			t.Errorf("%s: Unexpected error: %v", pattern, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package pathtree
+
+import "sort"
+
+// AddTag tags the leaf, eg. with "admin" to find all admin routes with
+// FindByTag. Adding a tag the leaf already has does nothing.
+func (l *Leaf) AddTag(tag string) {
+	if l.tags == nil {
+		l.tags = make(map[string]bool)
+	}
+	l.tags[tag] = true
+}
+
+// HasTag reports if the leaf was tagged with tag.
+func (l *Leaf) HasTag(tag string) bool {
+	return l.tags[tag]
+}
+
+// Tags returns the tags of the leaf, sorted.
+func (l *Leaf) Tags() []string {
+	if len(l.tags) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(l.tags))
+	for tag := range l.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// FindByTag returns the leafs tagged with tag, in the order of Walk.
+func (n *Node) FindByTag(tag string) []*Leaf {
+	var leafs []*Leaf
+	n.Walk(func(l *Leaf) {
+		if l.tags[tag] {
+			leafs = append(leafs, l)
+		}
+	})
+	return leafs
+}
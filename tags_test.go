@@ -0,0 +1,46 @@
+package pathtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/admin/users", 1)
+	l2, _ := n.Add("/admin/*rest", 2)
+	l3, _ := n.Add("/", 3)
+	l1.AddTag("admin")
+	l1.AddTag("internal")
+	l2.AddTag("admin")
+	l2.AddTag("admin")
+	l3.AddTag("public")
+
+	if leafs := n.FindByTag("admin"); !reflect.DeepEqual(leafs, []*Leaf{l2, l1}) {
+		t.Errorf("FindByTag(admin) (actual) %v", leafs)
+	}
+	if leafs := n.FindByTag("missing"); leafs != nil {
+		t.Errorf("FindByTag(missing) (actual) %v", leafs)
+	}
+	if !l1.HasTag("internal") || l2.HasTag("internal") {
+		t.Errorf("HasTag (actual) %v %v", l1.HasTag("internal"), l2.HasTag("internal"))
+	}
+	if tags := l1.Tags(); !reflect.DeepEqual(tags, []string{"admin", "internal"}) {
+		t.Errorf("Tags (actual) %v", tags)
+	}
+
+	c := n.Clone()
+	l1.AddTag("changed")
+	if leafs := c.FindByTag("admin"); len(leafs) != 2 || leafs[1].HasTag("changed") {
+		t.Errorf("Clone should copy tags (actual) %v", leafs)
+	}
+
+	export := c.Export()
+	if !strings.Contains(export, "/admin/users\t1\t#admin #internal\n") || !strings.Contains(export, "/\t3\t#public\n") {
+		t.Errorf("Export should list tags (actual) %s", export)
+	}
+	if routes := c.Routes(); !reflect.DeepEqual(routes[0].Tags, []string{"public"}) {
+		t.Errorf("Routes should list tags (actual) %v", routes[0].Tags)
+	}
+}
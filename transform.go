@@ -0,0 +1,33 @@
+package pathtree
+
+// SetTransform makes Find pass the expansions of the wildcards of the leaf
+// named name through fn before returning them, eg. strings.ToLower to
+// normalize IDs. Matching, constraints included, uses the expansions as they
+// are in the path. A nil fn removes the transform. Names the leaf has no
+// wildcard for are ignored.
+func (l *Leaf) SetTransform(name string, fn func(string) string) {
+	for i, wildcard := range l.Wildcards {
+		if wildcard.Name != name {
+			continue
+		}
+		if l.transforms == nil {
+			l.transforms = make([]func(string) string, len(l.Wildcards))
+		}
+		l.transforms[i] = fn
+	}
+}
+
+// Returns the expansions of the leaf with its transforms applied.
+func (l *Leaf) transform(expansions []string) []string {
+	if l == nil || l.transforms == nil {
+		return expansions
+	}
+	transformed := make([]string, len(expansions))
+	for i, value := range expansions {
+		if i < len(l.transforms) && l.transforms[i] != nil {
+			value = l.transforms[i](value)
+		}
+		transformed[i] = value
+	}
+	return transformed
+}
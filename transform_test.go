@@ -0,0 +1,34 @@
+package pathtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetTransform(t *testing.T) {
+	n := New()
+	l, _ := n.Add("/users/:[2]id/:name/:id", 1)
+	l.SetTransform("id", strings.ToLower)
+	l.SetTransform("name", strings.TrimSpace)
+	l.SetTransform("missing", strings.ToUpper)
+
+	if leaf, exp := n.Find("/users/AB/ x /CD"); leaf != l || !reflect.DeepEqual(exp, []string{"ab", "x", "cd"}) {
+		t.Errorf("Find (actual) %v %v", leaf, exp)
+	}
+	if leaf, _ := n.Find("/users/ABC/x/CD"); leaf != nil {
+		t.Errorf("Transforms should not affect constraints")
+	}
+	if leaf, exp, _, _ := n.FindDetailed("/users/AB/x/CD"); leaf != l || !reflect.DeepEqual(exp, []string{"ab", "x", "cd"}) {
+		t.Errorf("FindDetailed (actual) %v %v", leaf, exp)
+	}
+
+	c := n.Clone()
+	l.SetTransform("id", nil)
+	if _, exp := n.Find("/users/AB/x/CD"); !reflect.DeepEqual(exp, []string{"AB", "x", "CD"}) {
+		t.Errorf("Find without transform (actual) %v", exp)
+	}
+	if _, exp := c.Find("/users/AB/x/CD"); !reflect.DeepEqual(exp, []string{"ab", "x", "cd"}) {
+		t.Errorf("Clone should keep its transforms (actual) %v", exp)
+	}
+}
@@ -16,15 +16,37 @@
 // location with surrounding padding elements that preceed and end it.
 // Different kinds of wildcards are permitted:
 //   - :[min,max]var; - will match any single path element between legths min
-//     and max which must be numeric.
+//     and max which must be numeric. Lengths are in bytes, not characters.
 //   - :[length]var; - will match any single path element of the set length
 //     which be numeric.
 //   - :var; - will match any single path element of and length.
+//   - :var(a|b|c); - will match only one of the values listed, and may be
+//     combined with a length.
+//   - :@name; - will match as the wildcard defined with DefineWildcard.
+//   - :N{var} and :N-M{var} - a whole path element that will match from N to M
+//     path elements, joined by '/'.
+//   - #var - a whole path element that will match only digits, and may be
+//     combined with a length, eg. "#[1,20]id".
 //   - *var - names beginning with '*' will match one or more path elements.
 //            (however, no path elements may come after a star wildcard)
+//   - * - a star without a name is named after its position among the
+//     wildcards of the pattern, eg. "*1" for "/x/*" and "*2" for "/:a/*".
 // For backwards compadability the trailing ';' of the last wildcard can be left
 // off if there is no padding after it.
 //
+// Padding before the first wildcard anchors it to the start of the element, so
+// "Archive_:first;_all" doesn't match "XArchive_March_all". To allow anything
+// before the padding, start the element with a wildcard instead, eg.
+// ":prefix;Archive_:first;_all".
+//
+// A path element with wildcards can start with <=N> to bound its total length
+// to N, eg. "<=64>:a;-:b;-:c", which is checked before matching its padding.
+//
+// The last path elements with wildcards can end with '?' to make them optional,
+// eg. "/blog/:year/:month?/:day?", which adds a leaf with the same value for
+// each number of them present, so it also matches "/blog/2024" and
+// "/blog/2024/05" with the expansions of the wildcards present.
+//
 // Algorithm
 //
 // Paths are mapped to the tree in the following way:
@@ -41,234 +63,684 @@ package pathtree
 
 import (
 	"errors"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type Node struct {
-	edges  map[string]Edge // the various path elements leading out of this node with wildcard elements.
-	leaf   *Leaf           // if set, this is a terminal node for this leaf.
-	star   *Leaf           // if set, this path ends in a star.
-	leafs  int             // counter for # leafs in the tree
-	parent *Edge           // two way traversing
+	edges   map[string]*Edge // the various path elements leading out of this node with wildcard elements.
+	leaf    *Leaf            // if set, this is a terminal node for this leaf.
+	star    *Leaf            // if set, this path ends in a star.
+	leafs   int              // counter for # leafs in the tree
+	parent  *Edge            // two way traversing
+	conf    *config          // configuration shared by all nodes of the tree
+	base    string           // prefix stripped from paths before lookup
+	counts  bool             // if Find counts hits and misses
+	misses  int64            // # Finds without a result, if counting
+	lengths map[int][]*Edge  // the edges of fixed length wildcards by length
+	compact bool             // if Compact merged edges of this node
+	sorted  []*Edge          // the edges sorted by representation, for traversals
+	padded  *padIndex        // the wildcard edges by their first padding, see indexPrefix
+	nostar  bool             // if stars don't match paths reaching this node, see DisableStar
 }
 
 type Leaf struct {
-	Value     interface{} // the value associated with this node
-	Wildcards []Wildcard  // the wildcard names, in order they appear in the path
-	order     int         // the order this leaf was added
-	parent    *Node       // two way traversing
-	slashend  bool        // if the path ends with a slash
+	Value      interface{}            // the value associated with this node
+	Wildcards  []Wildcard             // the wildcard names, in order they appear in the path
+	order      int                    // the order this leaf was added
+	parent     *Node                  // two way traversing
+	slashend   bool                   // if the path ends with a slash
+	hits       int64                  // # Finds returning this leaf, if counting
+	active     func() bool            // if set, the leaf is only found while this returns true
+	head       *Leaf                  // if set, the leaf holding the versions this leaf is one of
+	versions   []*Leaf                // leafs for version ranges of the same path
+	hidden     bool                   // if the leaf only holds versions and has no value itself
+	minver     int                    // minimum version of a versioned leaf
+	maxver     int                    // maximum version of a versioned leaf
+	choices    []WeightedValue        // values to choose between, if added with AddWeighted
+	meta       map[string]interface{} // metadata set with SetMeta
+	methods    map[string]interface{} // values by method, if added with AddMethod
+	namespaces map[string]interface{} // values by namespace, if added with AddNS
+	failure    *Leaf                  // the leaf found when a path only violates the constraints of this one
+	failed     *Leaf                  // if set, the leaf whose constraint failures this leaf is found for
+	alias      *Leaf                  // if set, the leaf this leaf is an alias of
+	aliases    []*Leaf                // the aliases of this leaf
+	expires    time.Time              // when the leaf expires, if added with AddTTL
+	now        func() time.Time       // the clock telling if the leaf expired, if added with AddTTL
+	pattern    string                 // the pattern the leaf was added with
+	source     string                 // the pattern as passed to Add
+	stored     atomic.Value           // the value set with Store
+	fallback   bool                   // if the leaf was set with SetFallback
+	transforms []func(string) string  // the transforms of expansions by wildcard, set with SetTransform
+	tags       map[string]bool        // the tags added with AddTag
+	values     []interface{}          // the values of the leaf, if added with Append
 }
 
 type Edge struct {
-	node      *Node      // node for this wildcard element
-	padding   [][]string // possible padding elements between each var
-	wildcards []Wildcard // wildcard elements being the vars
-	wildend   bool       // if it ends with a wildcard
-	minorder  int        // minimum order value in this path
-	parent    *Node      // two way traversing
+	Segment           // the path element to match
+	node     *Node    // node for this wildcard element
+	minorder int      // minimum rank of the leafs in this path, see config.rank
+	parent   *Node    // two way traversing
+	repr     string   // the path element this edge was created from
+	literals []string // if set, the literal path elements this edge was compacted from
+	minspan  int      // the minimum number of path elements of a multi-element wildcard
+	maxspan  int      // if not 0, the maximum number of path elements of a multi-element wildcard
+	fixed    int      // if not 0, the length of the fixed length wildcard, indexed in lengths of the parent
+	prefix   string   // if set, the first padding of the edge, indexed in the padding index of the parent
+	stats    *profile // if set, counts the lookups through this edge, see EnableProfile
 }
 
 type Wildcard struct {
-	Name string // name of the wildcard
-	Min  int    // min size (0 for none)
-	Max  int    // max size (0 for none)
+	Name    string   // name of the wildcard
+	Min     int      // min size in bytes, or runes WithRuneLength (0 for none)
+	Max     int      // max size in bytes, or runes WithRuneLength (0 for none)
+	Enum    []string // the values allowed (nil for any)
+	Numeric bool     `json:",omitempty"` // if values must be all digits, for "#name"
+}
+
+// Reports if s is an allowed value for the wildcard.
+func (w *Wildcard) accepts(s string) bool {
+	return w.acceptsLength(s, len(s))
+}
+
+// Reports if s, of the given length, is an allowed value for the wildcard.
+func (w *Wildcard) acceptsLength(s string, length int) bool {
+	if (w.Min != 0 && length < w.Min) || (w.Max != 0 && length > w.Max) {
+		return false
+	}
+	if w.Numeric && !numeric(s) {
+		return false
+	}
+	if w.Enum == nil {
+		return true
+	}
+	for _, value := range w.Enum {
+		if value == s {
+			return true
+		}
+	}
+	return false
 }
 
-// New returns a new path tree.
-func New() *Node {
-	return &Node{edges: make(map[string]Edge)}
+// New returns a new path tree configured with the given options. Without
+// options paths are separated by '/' and matched as described above.
+func New(opts ...Option) *Node {
+	c := &config{sep: "/"}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &Node{edges: make(map[string]*Edge), conf: c}
 }
 
 // Adds a new wildcard element to the node and returns the node
-func (n *Node) addEdge(padding [][]string, wildcards []Wildcard, representation string, wildend bool, order int) *Node {
-	element := Edge{node: New(), padding: padding, wildcards: wildcards, wildend: wildend, minorder: order, parent: n}
-	element.node.parent = &element
-	n.edges[representation] = element
+func (n *Node) addEdge(segment Segment, representation string, rank int) *Node {
+	node, element := n.conf.arena.node(), n.conf.arena.edge()
+	*node = Node{edges: make(map[string]*Edge), conf: n.conf}
+	*element = Edge{Segment: segment, node: node, minorder: rank, parent: n, repr: representation, stats: n.conf.newProfile()}
+	element.node.parent = element
+	n.setEdge(element)
 	return element.node
 }
 
+// Adds the edge to the edges of the node, keeping them sorted.
+func (n *Node) setEdge(edge *Edge) {
+	n.edges[edge.repr] = edge
+	i := sort.Search(len(n.sorted), func(i int) bool { return n.sorted[i].repr >= edge.repr })
+	if i < len(n.sorted) && n.sorted[i].repr == edge.repr {
+		n.sorted[i] = edge
+	} else {
+		n.sorted = slices.Insert(n.sorted, i, edge)
+	}
+	n.padded.rescan(n.sorted)
+}
+
+// Removes the edge with the representation from the edges of the node.
+func (n *Node) deleteEdge(representation string) {
+	delete(n.edges, representation)
+	i := sort.Search(len(n.sorted), func(i int) bool { return n.sorted[i].repr >= representation })
+	if i < len(n.sorted) && n.sorted[i].repr == representation {
+		n.sorted = slices.Delete(n.sorted, i, i+1)
+	}
+	n.padded.rescan(n.sorted)
+}
+
 // Add a path and its associated value to the tree.
-//   - key must begin with "/"
+//   - key must begin with "/", or the separator of the tree
 //   - key must not duplicate any existing key.
 // Returns an error if those conditions do not hold.
 func (n *Node) Add(key string, val interface{}) (leaf *Leaf, err error) {
-	if key[0] != '/' {
-		return nil, errors.New("Path must begin with /")
+	p, err := n.conf.compile(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.AddPattern(p, val)
+}
+
+// AddPattern adds a pattern parsed by CompilePattern like Add, without parsing
+// it again unless the tree has other options than those it was parsed with.
+func (n *Node) AddPattern(p *Pattern, val interface{}) (leaf *Leaf, err error) {
+	if !n.conf.compiled(p) {
+		if p, err = n.conf.compile(p.source); err != nil {
+			return nil, err
+		}
+	}
+	if err = n.check(p); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= p.optional; i++ {
+		if slot := n.slotElements(p.elements[:len(p.elements)-i]); slot != nil && *slot != nil && !(*slot).hidden {
+			return nil, &DuplicatePathError{p.source, (*slot).Source()}
+		}
 	}
 	n.leafs++
-	elements, slashend := splitPath(key)
-	return n.add(n.leafs, elements, nil, slashend, val)
+	if leaf, err = n.insert(p, n.leafs, val); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= p.optional; i++ {
+		n.insert(p.without(i), n.leafs, val)
+	}
+	return leaf, nil
 }
 
-func (n *Node) add(order int, elements []string, wildcards []Wildcard, slashend bool, val interface{}) (leaf *Leaf, err error) {
-	// Create leaf at the end
-	if len(elements) == 0 {
-		if n.leaf != nil {
-			return nil, errors.New("duplicate path")
+// Adds the leaf of a pattern with the given order and value.
+func (n *Node) insert(p *Pattern, order int, val interface{}) (*Leaf, error) {
+	slot, leaf := n.add(order, p, 0, nil)
+	if *slot != nil {
+		if !(*slot).hidden {
+			return nil, &DuplicatePathError{p.source, (*slot).Source()}
 		}
-		n.leaf = &Leaf{
+		leaf = *slot
+		leaf.hidden = false
+	} else {
+		*slot = leaf
+	}
+	leaf.Value, leaf.source = val, p.source
+	if n.conf.onadd != nil {
+		n.conf.onadd(leaf.Pattern(), leaf)
+	}
+	return leaf, nil
+}
+
+// SetOnAdd sets a function called at the end of every successful Add to the
+// tree with the pattern and leaf added, eg. to log routes as they're
+// registered. A nil fn removes it.
+func (n *Node) SetOnAdd(fn func(pattern string, leaf *Leaf)) {
+	n.conf.onadd = fn
+}
+
+// CanAdd returns the error Add would return for the pattern, without changing
+// the tree, eg. to validate a set of routes before adding any of them.
+func (n *Node) CanAdd(key string) error {
+	p, err := n.parse(key)
+	if err != nil {
+		return err
+	}
+	elements := p.elements
+	for i, el := range elements {
+		if len(el) > 0 && el[0] == '*' {
+			elements = elements[:i+1]
+			break
+		}
+	}
+	for i := 0; i <= p.optional; i++ {
+		if slot := n.slotElements(elements[:len(elements)-i]); slot != nil && *slot != nil && !(*slot).hidden {
+			return &DuplicatePathError{key, (*slot).Source()}
+		}
+	}
+	return nil
+}
+
+// AddIf adds a path like Add whose leaf is only found while active returns
+// true. While it returns false Find treats the leaf as absent and falls through
+// to the next best match.
+//
+// active is called during Find whenever the leaf is a candidate, possibly from
+// several goroutines at once. A Find running while the result of active changes
+// sees either result.
+func (n *Node) AddIf(key string, val interface{}, active func() bool) (leaf *Leaf, err error) {
+	if leaf, err = n.Add(key, val); err == nil {
+		leaf.active = active
+	}
+	return leaf, err
+}
+
+// Parses a pattern being added, checking it against the limits of the tree.
+func (n *Node) parse(key string) (*Pattern, error) {
+	p, err := n.conf.compile(key)
+	if err != nil {
+		return nil, err
+	}
+	return p, n.check(p)
+}
+
+// Checks a parsed pattern against the limits and definitions of the tree.
+func (n *Node) check(p *Pattern) error {
+	if n.conf.maxdepth > 0 && len(p.elements) > n.conf.maxdepth {
+		return errors.New("path deeper than " + strconv.Itoa(n.conf.maxdepth) + " elements")
+	}
+	if n.conf.maxwildcards > 0 && p.wildcards > n.conf.maxwildcards {
+		return errors.New("path with more than " + strconv.Itoa(n.conf.maxwildcards) + " wildcards")
+	}
+	if n.conf.collapse && slices.Contains(p.elements, "") {
+		return errors.New("empty path element with collapsed separators")
+	}
+	if err := n.conf.checkSatisfiable(p); err != nil {
+		return err
+	}
+	return n.conf.checkDefined(p)
+}
+
+// Descends the tree along the elements of the pattern from the ith creating
+// edges as needed. Returns the slot for the leaf of the path, and a new leaf to
+// put in it.
+func (n *Node) add(order int, p *Pattern, i int, wildcards []Wildcard) (slot **Leaf, leaf *Leaf) {
+	// Create leaf at the end
+	if i == len(p.elements) {
+		leaf = n.conf.arena.leaf()
+		*leaf = Leaf{
 			order:     order,
-			Value:     val,
 			Wildcards: wildcards,
 			parent:    n,
-			slashend:  slashend,
+			slashend:  p.slashend,
 		}
-		return n.leaf, nil
+		leaf.pattern = leaf.buildPattern(false)
+		return &n.leaf, leaf
 	}
 
-	var el string
-	el, elements = elements[0], elements[1:]
+	el := p.parsed[i]
 
 	// Handle stars
-	if len(el) > 0 && el[0] == '*' {
-		if n.star != nil {
-			return nil, errors.New("duplicate path")
+	if el.star {
+		leaf = n.conf.arena.leaf()
+		name := el.repr[1:]
+		if name == "" {
+			name = "*" + strconv.Itoa(len(wildcards)+1)
 		}
-		n.star = &Leaf{
+		*leaf = Leaf{
 			order:     order,
-			Value:     val,
-			Wildcards: append(wildcards, Wildcard{el[1:], 0, 0}),
+			Wildcards: append(wildcards, Wildcard{Name: name}),
 			parent:    n,
-			slashend:  slashend,
+			slashend:  p.slashend,
 		}
-		return n.star, nil
+		leaf.pattern = leaf.buildPattern(true)
+		return &n.star, leaf
 	}
 
-	// Handle wildcards
-	// remove any ending wildcard charicter
-	if (len(el) > 0) && (el[len(el)-1] == ';') {
-		el = el[:len(el)-1]
+	// Handle wildcards, the segment being shared with the pattern unless it
+	// references definitions of the tree
+	segment := el.segment
+	if p.defined {
+		segment.wildcards = slices.Clone(segment.wildcards)
+		segment.resolve(n.conf)
 	}
-	parts := splitInput(el)
-	variables := make([]Wildcard, len(parts)/2)
-	paddings := make([][]string, len(variables)+len(parts)%2)
 
-	// Split appart padding and variables (padding first even if empty)
-	in := false
-	key := 0
-	for _, value := range parts {
-		if in == false {
-			paddings[key] = splitPad(value)
+	// Test if map contains representation else create it
+	item, ok := n.edges[el.repr]
+	if !ok {
+		item, ok = n.expand(el.repr)
+	}
+	var node *Node
+	if rank := n.conf.rank(order); ok {
+		node = item.node
+		if item.minorder > rank {
+			item.minorder = rank
+		}
+	} else {
+		node = n.addEdge(segment, el.repr, rank)
+		node.parent.minspan, node.parent.maxspan = el.minspan, el.maxspan
+		n.indexLength(node.parent)
+		n.indexPrefix(node.parent)
+		if n.conf.onhotspot != nil && len(segment.wildcards) > 0 {
+			n.checkHotspot()
+		}
+	}
+
+	return node.add(order, p, i+1, append(wildcards, segment.wildcards...))
+}
+
+// Get returns the leaf added with the given pattern, or nil if there is none.
+// Unlike Find, the pattern is compared with the patterns in the tree rather
+// than matched against them.
+func (n *Node) Get(pattern string) *Leaf {
+	if slot := n.slot(pattern); slot != nil && *slot != nil && !(*slot).hidden {
+		return *slot
+	}
+	return nil
+}
+
+// Update replaces the value of the leaf added with the given pattern, through
+// Store if it was used for the leaf.
+func (n *Node) Update(pattern string, val interface{}) (leaf *Leaf, err error) {
+	if leaf = n.Get(pattern); leaf == nil {
+		return nil, errors.New("path not found")
+	}
+	if leaf.alias != nil {
+		leaf = leaf.alias
+	}
+	leaf.set(val)
+	return leaf, nil
+}
+
+// Remove removes the leaf added with the given pattern from the tree, along
+// with any nodes left without leafs. Versions added for the pattern with
+// AddVersioned are kept.
+func (n *Node) Remove(pattern string) (leaf *Leaf, err error) {
+	slot := n.slot(pattern)
+	if slot == nil || *slot == nil || (*slot).hidden {
+		return nil, errors.New("path not found")
+	}
+
+	leaf = *slot
+	if leaf.alias != nil {
+		leaf.unalias()
+	}
+	for _, alias := range leaf.aliases {
+		if alias.parent.star == alias {
+			alias.parent.star = nil
 		} else {
-			variables[key] = decodeWildcard(value)
-			key++
+			alias.parent.leaf = nil
 		}
-		in = !in
+		alias.parent.prune()
+	}
+	leaf.aliases = nil
+	if len(leaf.versions) > 0 {
+		head := &Leaf{}
+		*head = *leaf
+		head.Value, head.hidden = nil, true
+		for _, version := range head.versions {
+			version.head = head
+		}
+		*slot = head
+		return leaf, nil
 	}
+	*slot = nil
+	leaf.parent.prune()
+	return leaf, nil
+}
 
-	// Create string representation for map
-	wildend := len(paddings) == len(variables)
+// Returns the slot holding the leaf for a pattern, or nil if the pattern isn't
+// in the tree.
+func (n *Node) slot(pattern string) **Leaf {
+	if len(pattern) == 0 || pattern[0] != n.conf.sep[0] {
+		return nil
+	}
+	elements, _, _ := n.conf.split(pattern)
+	return n.slotElements(elements)
+}
 
-	// Test if map contains representation else create it
-	item, ok := n.edges[el]
-	var node *Node
-	if ok {
-		node = item.node
-		if item.minorder > order {
-			item.minorder = order
+func (n *Node) slotElements(elements []string) **Leaf {
+	if len(elements) == 0 {
+		return &n.leaf
+	}
+
+	el := elements[0]
+	if len(el) > 0 && el[0] == '*' {
+		if len(elements) > 1 {
+			return nil
+		}
+		return &n.star
+	}
+	if (len(el) > 0) && (el[len(el)-1] == ';') {
+		el = el[:len(el)-1]
+	}
+
+	if edge, ok := n.edges[el]; ok {
+		return edge.node.slotElements(elements[1:])
+	}
+	for _, edge := range n.sortedEdges() {
+		if edge.literals == nil || edge.literals[0] != el || len(elements) < len(edge.literals) {
+			continue
+		}
+		if slot := edge.node.findLiteralSlot(edge.literals[1:], elements[1:]); slot != nil {
+			return slot
 		}
-	} else {
-		node = n.addEdge(paddings, variables, el, wildend, order)
 	}
+	return nil
+}
 
-	return node.add(order, elements, append(wildcards, variables...), slashend, val)
+func (n *Node) findLiteralSlot(literals, elements []string) **Leaf {
+	for i, literal := range literals {
+		if elements[i] != literal {
+			return nil
+		}
+	}
+	return n.slotElements(elements[len(literals):])
+}
+
+// Removes this node and its empty parents from the tree if they have no leafs
+// or edges left.
+func (n *Node) prune() {
+	for n.parent != nil && n.leaf == nil && n.star == nil && len(n.edges) == 0 {
+		edge := n.parent
+		edge.parent.deleteEdge(edge.repr)
+		edge.parent.unindexLength(edge)
+		edge.parent.unindexPrefix(edge)
+		n = edge.parent
+	}
+}
+
+// Reports if the leaf is currently active.
+func (l *Leaf) enabled() bool {
+	return (l.active == nil || l.active()) && !l.Expired()
+}
+
+// Walk calls fn for every leaf in the tree, stars included. Each node's leaf
+// comes first, then its star, then the nodes below it with their edges sorted
+// by pattern, so the order is the same for every tree with the same patterns.
+func (n *Node) Walk(fn func(leaf *Leaf)) {
+	n.walk(fn)
 }
 
 // Find a given path. Any wildcards traversed along the way are expanded and
-// returned, along with the value.
+// returned, along with the value. If nothing matches, the fallback set with
+// SetFallback for the path is returned, if any.
 func (n *Node) Find(key string) (leaf *Leaf, expansions []string) {
-	if len(key) == 0 || key[0] != '/' {
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
 		return nil, nil
 	}
 
-	elements, _ := splitPath(key)
-	return n.find(elements, nil)
+	var q *query
+	if n.conf.strict || n.conf.starslash {
+		q = &query{strict: n.conf.strict, slashend: slashend}
+	}
+	return n.findPath(elements, slashend, q)
 }
 
-func (n *Node) find(elements, exp []string) (leaf *Leaf, expansions []string) {
+// Finds a path split into elements, falling back to constraint failures and
+// fallbacks, and counting the result.
+func (n *Node) findPath(elements []string, slashend bool, q *query) (leaf *Leaf, expansions []string) {
+	var ext string
+	if n.conf.ext && !slashend {
+		leaf, expansions, ext = n.findExtension(elements, q)
+	}
+	if leaf == nil {
+		var exp []string
+		if q != nil {
+			exp = q.stack
+		}
+		leaf, expansions = n.find(elements, exp, q)
+	} else if whole, exp := n.findExact(elements, q); whole != nil {
+		leaf, expansions, ext = whole, exp, ""
+	}
+	if q != nil && q.err != nil {
+		return nil, nil
+	}
+	if leaf == nil && n.conf.failures {
+		leaf, expansions = n.findFailure(elements, slashend)
+	}
+	if n.counts {
+		n.count(leaf)
+	}
+	if leaf == nil && n.conf.fallbacks != nil {
+		leaf = n.findFallback(elements)
+	}
+	if q != nil && q.path != "" && leaf != nil && leaf.isStar() && len(expansions) > 0 {
+		q.starlen = len(expansions[len(expansions)-1])
+	}
+	if n.conf.ext && leaf != nil {
+		expansions = append(expansions[:len(expansions):len(expansions)], ext)
+	}
+	if q != nil && q.path != "" {
+		q.raw = expansions
+	}
+	return leaf, leaf.transform(expansions)
+}
+
+func (n *Node) find(elements, exp []string, q *query) (leaf *Leaf, expansions []string) {
+	if q != nil {
+		if q.trace {
+			q.visit(n, len(elements))
+		}
+		if q.ctx != nil && q.cancelled() {
+			return nil, nil
+		}
+	}
 	if len(elements) == 0 {
-		return n.leaf, exp
+		if leaf = q.resolve(n.leaf); leaf == nil {
+			return nil, nil
+		}
+		return leaf, exp
 	}
 
 	// If this node has a star, calculate the star expansions in advance.
 	var starExpansion string
-	if n.star != nil {
-		starExpansion = strings.Join(elements, "/")
+	if n.star != nil && q != nil && q.path != "" {
+		starExpansion = q.star(elements, n.conf.sep, n.conf.starslash)
+	} else if n.star != nil {
+		starExpansion = strings.Join(elements, n.conf.sep)
+		if n.conf.starslash && q != nil && q.slashend {
+			starExpansion += n.conf.sep
+		}
 	}
 
+	// Stars can't match paths into subtrees they're disabled for, nor the last
+	// element where only literals may
+	locked := (n.conf.nostars && n.star != nil && (n.disabled() || n.locked(elements))) || (q != nil && q.literal)
+
 	// Peel off the next element and look up the associated edge.
 	var el string
 	el, elements = elements[0], elements[1:]
-
-	// Handle star
-	if n.star != nil && (leaf == nil || leaf.order > n.star.order) {
-		leaf = n.star
-		expansions = append(exp, starExpansion)
+	if q != nil && q.literal && len(elements) == 0 {
+		return n.findLiteral(el, exp, q)
 	}
 
-	// Handle wildards
-	for _, value := range n.edges {
-		// Only check if tree contrains lower order item
-		if leaf != nil && leaf.order < value.minorder {
-			continue
+	// Handle star, unless deferred until no edge matches. Expansions are
+	// appended to exp through the query, which keeps those of the leaf found
+	// from being overwritten by the edges of this node.
+	star := n.star != nil && (starExpansion != "" || !n.conf.nonempty) && !locked
+	if star && n.conf.stars != StarDeferred {
+		if leaf = q.resolve(n.star); leaf != nil {
+			expansions = q.keep(q.extend(exp, starExpansion))
+			if n.conf.stars == StarEager {
+				return leaf, expansions
+			}
 		}
+	}
 
-		found := false
-		variables := make([]string, 0, 0)
-		input := el
-
-		// Check all padding elements are present and exit at first failure
-		for count, pads := range value.padding {
-			for _, pad := range pads {
-				pos := strings.Index(input, pad)
-
-				if (pos == -1) || (count == 0 && pos > 0) {
-					found = false
-					continue
-				}
-
-				if count != 0 {
-					item := &value.wildcards[count-1]
-					if (item.Min != 0 && pos < item.Min) || (item.Max != 0 && pos > item.Max) {
-						found = false
-						continue
-					} else {
-						variables = append(variables, input[:pos])
-					}
+	// Handle wildards, fixed length ones through the length index unless
+	// their lengths are ignored or counted in runes, and those starting with
+	// padding through the padding index unless padding isn't compared bytewise
+	indexed := (q == nil || !q.relaxed) && !n.conf.runes
+	prefixed := n.padded != nil && !n.conf.fold && n.conf.segeq == nil
+	scan := n.sorted
+	if prefixed {
+		scan = n.padded.scan
+	}
+	for _, value := range scan {
+		if value.fixed == 0 || !indexed {
+			leaf, expansions = value.find(el, elements, exp, q, leaf, expansions)
+		}
+	}
+	if indexed {
+		for _, value := range n.lengths[len(el)] {
+			leaf, expansions = value.find(el, elements, exp, q, leaf, expansions)
+		}
+	}
+	if prefixed {
+		for length := range n.padded.lengths {
+			if length <= len(el) {
+				for _, value := range n.padded.edges[el[:length]] {
+					leaf, expansions = value.find(el, elements, exp, q, leaf, expansions)
 				}
-				input = input[pos+len(pad):]
-				found = true
-				break
-			}
-
-			if !found {
-				break
 			}
 		}
-
-		if !found {
-			continue
+	}
+	if star && leaf == nil && n.conf.stars == StarDeferred {
+		if leaf = q.resolve(n.star); leaf != nil {
+			expansions = q.keep(q.extend(exp, starExpansion))
 		}
+	}
 
-		if value.wildend {
-			item := &value.wildcards[len(value.wildcards)-1]
-			if value.wildend && ((item.Min != 0 && len(input) < item.Min) || (item.Max != 0 && len(input) > item.Max)) {
-				continue
-			}
-			variables = append(variables, input)
-		}
+	return
+}
 
-		// Set leaf if it meets lower levels
-		if testleaf, testexpansions := value.node.find(elements, append(exp, variables...)); testleaf != nil {
-			if leaf == nil || leaf.order > testleaf.order {
-				leaf, expansions = testleaf, testexpansions
-			}
+// Finds the leaf for the elements through the edge, starting with el, and
+// returns it if it has priority over leaf, or else leaf.
+func (e *Edge) find(el string, elements, exp []string, q *query, leaf *Leaf, expansions []string) (*Leaf, []string) {
+	// Only check if tree contrains lower order item
+	if leaf != nil && e.parent.conf.rank(leaf.order) < e.minorder {
+		e.stats.prune()
+		return leaf, expansions
+	}
+	e.stats.examine()
+
+	var testleaf *Leaf
+	var testexpansions []string
+	switch {
+	case e.maxspan > 0:
+		// Multi-element wildcards match several elements at once
+		testleaf, testexpansions = e.findSpan(el, elements, exp, q, e.parent.conf)
+		if testleaf != nil {
+			e.stats.match()
+		}
+	case e.literals != nil:
+		// Compacted edges match several literal elements at once
+		if !e.parent.conf.equal(e.literals[0], el) || len(elements) < len(e.literals)-1 {
+			return leaf, expansions
 		}
+		e.stats.match()
+		testleaf, testexpansions = e.node.findLiterals(e.literals[1:], elements, exp, q)
+	default:
+		variables, ok := e.expand(el, exp, q)
+		if !ok {
+			return leaf, expansions
+		}
+		e.stats.match()
+		testleaf, testexpansions = e.node.find(elements, variables, q)
 	}
 
-	return
+	// Set leaf if it meets lower levels
+	if testleaf != nil && (leaf == nil || e.parent.conf.ranks(testleaf, leaf)) {
+		return testleaf, q.keep(testexpansions)
+	}
+	return leaf, expansions
+}
+
+// Matches el against the edge, returning exp with the expansions of its
+// wildcards appended through the query, without allocating them if it has a
+// stack.
+func (e *Edge) expand(el string, exp []string, q *query) ([]string, bool) {
+	if q == nil || q.stack == nil {
+		variables, ok := e.match(el, e.parent.conf, q != nil && q.relaxed)
+		if !ok {
+			return nil, false
+		}
+		return q.extend(exp, variables...), true
+	}
+	spans, ok := e.bounds(el, e.parent.conf, q.relaxed, q.spans[:0])
+	if !ok {
+		return nil, false
+	}
+	q.spans = spans
+	for _, span := range spans {
+		exp = append(exp, el[span.Start:span.End])
+	}
+	return exp, true
 }
 
 // Reverse a given leaf into a path traversing up the tree. Any wildcards along
@@ -276,52 +748,310 @@ func (n *Node) find(elements, exp []string) (leaf *Leaf, expansions []string) {
 // err is nil on success, returns an array of missing wildcard elements not found
 // in the variable map or an empty array if leaf is invalid.
 func (n *Node) Reverse(leaf *Leaf, variables map[string]string) (path string, unused map[string]string, err []string) {
+	return n.reverseWith(leaf, variables, true, nil)
+}
+
+// Reverses the leaf like Reverse, deleting the used variables from the map if
+// consume is set, and substituting the wildcards with the values passed
+// through encode if set.
+func (n *Node) reverseWith(leaf *Leaf, variables map[string]string, consume bool, encode func(string) string) (path string, unused map[string]string, err []string) {
 	if leaf == nil || leaf.parent == nil {
 		return "", variables, make([]string, 0, 0)
 	}
 
-	return leaf.parent.reverse("", variables, nil, leaf.slashend)
+	// Stars must expand to at least one path element
+	var exp string
+	var missed []string
+	if leaf.isStar() {
+		star := leaf.Wildcards[len(leaf.Wildcards)-1]
+		if item, ok := variables[star.Name]; ok && item != "" {
+			if encode != nil {
+				elements := strings.Split(item, n.conf.sep)
+				for i, el := range elements {
+					elements[i] = encode(el)
+				}
+				item = strings.Join(elements, n.conf.sep)
+			}
+			exp = n.conf.sep + item
+			if consume {
+				delete(variables, star.Name)
+			}
+		} else {
+			missed = append(missed, "[0,0]"+star.Name)
+		}
+	}
+
+	slashend := leaf.slashend && !(n.conf.starslash && strings.HasSuffix(exp, n.conf.sep))
+	path, unused, err = leaf.parent.reverse(exp, variables, missed, slashend, consume, encode)
+	if ext := variables["ext"]; n.conf.ext && ext != "" && !strings.HasSuffix(path, n.conf.sep) {
+		if encode != nil {
+			ext = encode(ext)
+		}
+		path += "." + ext
+		if consume {
+			delete(variables, "ext")
+		}
+	}
+	return n.base + path, unused, err
 }
 
-func (n *Node) reverse(exp string, variables map[string]string, missed []string, slashend bool) (path string, unused map[string]string, err []string) {
+// Reverses up the tree from this node. Used variables are deleted from the map
+// if consume is set, and substituted passed through encode if set.
+func (n *Node) reverse(exp string, variables map[string]string, missed []string, slashend, consume bool, encode func(string) string) (path string, unused map[string]string, err []string) {
 	// Return if we have reached the end of a tree
 	if n.parent == nil {
 		if slashend {
-			exp += "/"
+			exp += n.conf.sep
 		}
 		return exp, variables, missed
 	}
 
-	// Generate edge path from padding and variables
+	// Generate edge path from padding and variables. The padding before each
+	// wildcard has its index, the one after the last wildcard comes last.
 	edge := n.parent
 	var output string
 	for key, value := range edge.wildcards {
 		item, ok := variables[value.Name]
-		if !ok || (value.Min != 0 && len(item) < value.Min) || (value.Max != 0 && len(item) > value.Max) {
+		if !ok || !n.conf.accepts(&value, item) || !edge.spans(item, n.conf.sep) {
 			item = ""
 			ok = false
 			missed = append(missed, "["+strconv.Itoa(value.Min)+","+strconv.Itoa(value.Max)+"]"+value.Name)
+		} else if encode != nil {
+			item = encode(item)
 		}
 
 		output = output + edge.padding[key][0] + item
 
-		if ok {
+		if ok && consume {
 			delete(variables, value.Name)
 		}
 	}
 
 	// Generate total output and add any final padding
-	if edge.wildend {
-		exp = "/" + output + exp
-	} else {
-		exp = "/" + output + edge.padding[len(edge.padding)-1][0] + exp
+	if !edge.wildend {
+		output += edge.padding[len(edge.wildcards)][0]
+	}
+	if edge.maxlen > 0 && n.conf.length(output) > edge.maxlen {
+		missed = append(missed, edge.repr)
+	}
+	exp = n.conf.sep + output + exp
+
+	return edge.parent.reverse(exp, variables, missed, slashend, consume, encode)
+}
+
+// Calls fn for every leaf and star in the tree below this node.
+func (n *Node) walk(fn func(*Leaf)) {
+	if n.leaf != nil {
+		n.leaf.each(fn)
+	}
+	if n.star != nil {
+		n.star.each(fn)
+	}
+	for _, edge := range n.sortedEdges() {
+		edge.node.walk(fn)
+	}
+}
+
+// Returns the edges of this node sorted by representation. Every traversal of
+// the tree visits edges in this order, so it's the same for every tree with the
+// same patterns whatever order they were added in. The slice must not be
+// changed.
+func (n *Node) sortedEdges() []*Edge {
+	return n.sorted
+}
+
+// Returns the edges leading from the root to this leaf, in path order.
+func (l *Leaf) edges() []*Edge {
+	var edges []*Edge
+	for node := l.parent; node != nil && node.parent != nil; node = node.parent.parent {
+		edges = append(edges, node.parent)
 	}
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+	return edges
+}
+
+// Pattern returns the pattern this leaf was added with, as reconstructed when
+// it was added. Optional ';' terminators are omitted.
+func (l *Leaf) Pattern() string {
+	if l.pattern == "" {
+		return l.buildPattern(l.isStar())
+	}
+	return l.pattern
+}
+
+// Returns the path element of a star with the given name, which is "*" for the
+// names given to stars without one.
+func starElement(name string) string {
+	if strings.HasPrefix(name, "*") {
+		return "*"
+	}
+	return "*" + name
+}
+
+// Source returns the pattern the leaf was added with exactly as it was passed
+// to Add, unlike Pattern, or Pattern if it wasn't added with a pattern.
+func (l *Leaf) Source() string {
+	if l.source == "" {
+		return l.Pattern()
+	}
+	return l.source
+}
+
+// DuplicatePathError is the error adding a pattern with the same leaf as a
+// pattern already added.
+type DuplicatePathError struct {
+	Pattern  string // the pattern added
+	Existing string // the source of the pattern already added
+}
+
+func (e *DuplicatePathError) Error() string {
+	return "duplicate path " + strconv.Quote(e.Pattern) + ": already added as " + strconv.Quote(e.Existing)
+}
+
+// Builds the pattern of the leaf from its edges.
+func (l *Leaf) buildPattern(star bool) string {
+	var pattern string
+	sep := l.parent.conf.sep
+	for _, edge := range l.edges() {
+		pattern += sep + edge.repr
+		if edge.wildend && l.parent.conf.canonical {
+			pattern += ";"
+		}
+	}
+	if star {
+		pattern += sep + starElement(l.Wildcards[len(l.Wildcards)-1].Name)
+	}
+	if l.slashend || pattern == "" {
+		pattern += sep
+	}
+	return pattern
+}
+
+// Parent returns the leaf registered one path element above this one, or nil
+// if there is none. The leaf at the root has no parent. A star's parent is the
+// leaf of the node it hangs from, as the star takes the place of an element.
+func (l *Leaf) Parent() *Leaf {
+	if l.parent == nil {
+		return nil
+	}
+	if l.isStar() {
+		return l.parent.leaf
+	}
+	if l.parent.parent == nil || l.parent.parent.literals != nil {
+		return nil
+	}
+	return l.parent.parent.parent.leaf
+}
+
+// Returns the root node of the tree this leaf is in.
+func (l *Leaf) root() *Node {
+	node := l.parent
+	for node.parent != nil {
+		node = node.parent.parent
+	}
+	return node
+}
+
+// Returns the number of path elements from the root to this leaf, not
+// counting a star.
+func (l *Leaf) depth() int {
+	depth := 0
+	for _, edge := range l.edges() {
+		depth += edge.width()
+	}
+	return depth
+}
+
+// Reports if this leaf is the star of its node.
+func (l *Leaf) isStar() bool {
+	if l.head != nil {
+		return l.head.isStar()
+	}
+	if l.failed != nil {
+		return l.failed.isStar()
+	}
+	return l.parent != nil && l.parent.star == l
+}
 
-	return edge.parent.reverse(exp, variables, missed, slashend)
+// DebugSegment parses a single path element of a pattern the way Add does and
+// returns its padding, with '|' separated alternatives, and its wildcards. The
+// padding always comes first, so a pattern starting with a wildcard has empty
+// leading padding, and there is padding after the last wildcard unless the
+// element ends with it.
+func DebugSegment(pattern string) (padding []string, wildcards []Wildcard) {
+	if (len(pattern) > 0) && (pattern[len(pattern)-1] == ';') {
+		pattern = pattern[:len(pattern)-1]
+	}
+	paddings, wildcards, _ := parseElement(pattern)
+	padding = make([]string, len(paddings))
+	for i, pads := range paddings {
+		padding[i] = strings.Join(pads, "|")
+	}
+	return padding, wildcards
 }
 
-func splitPath(key string) (parts []string, slashend bool) {
-	elements := strings.Split(key, "/")
+// Splits a path element into its padding and wildcards.
+func parseElement(el string) (paddings [][]string, variables []Wildcard, wildend bool) {
+	if isNumeric(el) {
+		wildcard := decodeWildcard(strings.TrimSuffix(el[1:], ";"))
+		wildcard.Numeric = true
+		return [][]string{{""}}, []Wildcard{wildcard}, true
+	}
+	parts := splitInput(el)
+	variables = make([]Wildcard, len(parts)/2)
+	paddings = make([][]string, len(variables)+len(parts)%2)
+
+	// Split appart padding and variables (padding first even if empty)
+	in := false
+	key := 0
+	for _, value := range parts {
+		if in == false {
+			paddings[key] = splitPad(value)
+		} else {
+			variables[key] = decodeWildcard(value)
+			key++
+		}
+		in = !in
+	}
+
+	// Create string representation for map
+	wildend = len(paddings) == len(variables)
+	return paddings, variables, wildend
+}
+
+// Splits a path being looked up into its elements. Returns false if it can't
+// match any path in the tree.
+func (n *Node) lookup(key string) (elements []string, slashend bool, ok bool) {
+	if key, ok = n.relative(key); !ok {
+		return nil, false, false
+	}
+	elements, slashend = splitPath(key, n.conf.sep)
+	return elements, slashend, true
+}
+
+// Strips the base path off a path being looked up. Returns false if it can't
+// match any path in the tree.
+func (n *Node) relative(key string) (string, bool) {
+	sep := n.conf.sep
+	if n.base != "" {
+		if !strings.HasPrefix(key, n.base) || (len(key) > len(n.base) && key[len(n.base)] != sep[0]) {
+			return "", false
+		}
+		key = key[len(n.base):]
+		if key == "" {
+			key = sep
+		}
+	}
+	if len(key) == 0 || key[0] != sep[0] {
+		return "", false
+	}
+	return n.conf.collapsed(key), true
+}
+
+func splitPath(key, sep string) (parts []string, slashend bool) {
+	elements := strings.Split(key, sep)
 	slashend = false
 	if elements[0] == "" {
 		elements = elements[1:]
@@ -374,7 +1104,19 @@ func splitPad(s string) []string {
 }
 
 func decodeWildcard(s string) Wildcard {
-	if s[0] == '[' && len(s) > 2 {
+	var enum []string
+	if open := strings.IndexByte(s, '('); open >= 0 && s[len(s)-1] == ')' {
+		enum = strings.Split(s[open+1:len(s)-1], "|")
+		s = s[:open]
+	}
+
+	wildcard := decodeBounds(s)
+	wildcard.Enum = enum
+	return wildcard
+}
+
+func decodeBounds(s string) Wildcard {
+	if len(s) > 2 && s[0] == '[' {
 		min, max := 0, 0
 		var err error
 
@@ -383,18 +1125,18 @@ func decodeWildcard(s string) Wildcard {
 
 		if pos1 == -1 && pos2 > 0 && len(s) > pos2+2 {
 			if min, err = strconv.Atoi(s[1 : pos2+1]); err == nil {
-				return Wildcard{s[pos2+2:], min, min}
+				return Wildcard{Name: s[pos2+2:], Min: min, Max: min}
 			}
 		}
 
 		if pos1 > 0 && pos2 > pos1+1 && len(s) > pos2+2 {
 			if min, err = strconv.Atoi(s[1 : pos1+1]); err == nil {
 				if max, err = strconv.Atoi(s[pos1+2 : pos2+1]); err == nil {
-					return Wildcard{s[pos2+2:], min, max}
+					return Wildcard{Name: s[pos2+2:], Min: min, Max: max}
 				}
 			}
 		}
 	}
 
-	return Wildcard{s, 0, 0}
+	return Wildcard{Name: s}
 }
@@ -1,6 +1,7 @@
 package pathtree
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -140,6 +141,25 @@ func TestReverse(t *testing.T) {
 	}
 }
 
+func TestReversePadding(t *testing.T) {
+	n := New()
+
+	l1, _ := n.Add("/:first;_all", 1)
+	l2, _ := n.Add("/a_:x;_b_:y;_c", 2)
+	l3, _ := n.Add("/b_:x;-:y", 3)
+	l4, _ := n.Add("/c/:x;_:y;|:z;.html|.htm", 4)
+
+	reverse(t, n, l1, map[string]string{"first": "March"}, "/March_all", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{}, "/_all", map[string]string{}, []string{"[0,0]first"})
+	reverse(t, n, l2, map[string]string{"x": "1", "y": "2"}, "/a_1_b_2_c", map[string]string{}, nil)
+	reverse(t, n, l3, map[string]string{"x": "1", "y": "2"}, "/b_1-2", map[string]string{}, nil)
+	reverse(t, n, l4, map[string]string{"x": "1", "y": "2", "z": "3"}, "/c/1_23.html", map[string]string{}, nil)
+
+	found(t, n, "/March_all", []string{"March"}, 1)
+	found(t, n, "/a_1_b_2_c", []string{"1", "2"}, 2)
+	found(t, n, "/b_1-2", []string{"1", "2"}, 3)
+}
+
 func BenchmarkTree100(b *testing.B) {
 	n := New()
 	n.Add("/", "root")
@@ -220,6 +240,32 @@ func found(t *testing.T, n *Node, p string, expectedExpansions []string, val int
 	if leaf.Value != val {
 		t.Errorf("%s: Value (actual) %v != %v (expected)", p, leaf.Value, val)
 	}
+	if names := leaf.WildcardNames(); len(names) != len(expansions) {
+		t.Errorf("%s: Wildcard names %v don't line up with expansions %v", p, names, expansions)
+	}
+	matchRegexp(t, leaf, p, expansions)
+}
+
+func matchRegexp(t *testing.T, leaf *Leaf, p string, expansions []string) {
+	if leaf.root().conf.segeq != nil {
+		return
+	}
+	re, err := leaf.Regexp()
+	if err != nil {
+		t.Errorf("%s: Regexp failed to compile: %v", p, err)
+		return
+	}
+	match := re.FindStringSubmatch(p)
+	if match == nil {
+		t.Errorf("%s: Regexp %s doesn't match", p, re)
+		return
+	}
+	if len(match) > 1 && !reflect.DeepEqual(match[1:], expansions) {
+		t.Errorf("%s: Regexp %s captures (actual) %v != %v (expected)", p, re, match[1:], expansions)
+	}
+	if names := re.SubexpNames(); len(names) > 1 && !reflect.DeepEqual(names[1:], leaf.WildcardNames()) {
+		t.Errorf("%s: Regexp %s groups (actual) %v != %v (wildcard names)", p, re, names[1:], leaf.WildcardNames())
+	}
 }
 
 func reverse(t *testing.T, n *Node, l *Leaf, vars map[string]string, path string, unused map[string]string, missing []string) {
@@ -234,3 +280,408 @@ func reverse(t *testing.T, n *Node, l *Leaf, vars map[string]string, path string
 		t.Errorf("%s: Missing expansions (actual) %v != %v (expected)", l.Value, r_missing, missing)
 	}
 }
+
+func TestParent(t *testing.T) {
+	n := New()
+
+	l1, _ := n.Add("/", 1)
+	l2, _ := n.Add("/a", 2)
+	l3, _ := n.Add("/a/:b/c", 3)
+	l4, _ := n.Add("/a/:b", 4)
+	l5, _ := n.Add("/a/*rest", 5)
+	l6, _ := n.Add("/x/y", 6)
+
+	if l1.Parent() != nil {
+		t.Errorf("Root leaf should not have a parent")
+	}
+	if l2.Parent() != l1 {
+		t.Errorf("Parent of /a should be /")
+	}
+	if l3.Parent() != l4 {
+		t.Errorf("Parent of /a/:b/c should be /a/:b")
+	}
+	if l4.Parent() != l2 {
+		t.Errorf("Parent of /a/:b should be /a")
+	}
+	if l5.Parent() != l2 {
+		t.Errorf("Parent of /a/*rest should be /a")
+	}
+	if l6.Parent() != nil {
+		t.Errorf("Parent of /x/y should be nil")
+	}
+}
+
+func TestReverseStar(t *testing.T) {
+	n := New()
+
+	l1, _ := n.Add("/files/*path", 1)
+	l2, _ := n.Add("/*all/", 2)
+
+	reverse(t, n, l1, map[string]string{"path": "a/b.css"}, "/files/a/b.css", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{}, "/files", map[string]string{}, []string{"[0,0]path"})
+	reverse(t, n, l2, map[string]string{"all": "x"}, "/x/", map[string]string{}, nil)
+}
+
+func TestAddIf(t *testing.T) {
+	n := New()
+
+	enabled := false
+	n.AddIf("/beta/:id", 1, func() bool { return enabled })
+	n.Add("/:section/:id", 2)
+	n.AddIf("/*all", 3, func() bool { return enabled })
+	n.AddIf("/", 4, func() bool { return enabled })
+
+	found(t, n, "/beta/7", []string{"beta", "7"}, 2)
+	notfound(t, n, "/beta/7/x")
+	notfound(t, n, "/")
+
+	enabled = true
+	found(t, n, "/beta/7", []string{"7"}, 1)
+	found(t, n, "/beta/7/x", []string{"beta/7/x"}, 3)
+	found(t, n, "/", nil, 4)
+
+	if _, err := n.AddIf("/beta/:id", 5, func() bool { return true }); err == nil {
+		t.Errorf("Expected duplicate path error")
+	}
+}
+
+func TestTrailingSlashDuplicate(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithStrictSlash()}} {
+		n := New(opts...)
+		for _, pair := range [][2]string{
+			{"/foo", "/foo/"},
+			{"/bar/", "/bar"},
+			{"/a/:id", "/a/:id/"},
+			{"/files/*path", "/files/*path/"},
+		} {
+			l, err := n.Add(pair[0], 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := fmt.Sprintf("duplicate path %q: already added as %q", pair[1], pair[0])
+			if _, err := n.Add(pair[1], 2); err == nil || err.Error() != expected {
+				t.Errorf("%s after %s: Expected duplicate path error (actual) %v", pair[1], pair[0], err)
+			}
+			if l.Pattern() != pair[0] {
+				t.Errorf("%s: The duplicate should not change the pattern (actual) %s", pair[0], l.Pattern())
+			}
+		}
+	}
+}
+
+func TestDebugSegment(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		padding   []string
+		wildcards []Wildcard
+	}{
+		{":id;really", []string{"", "really"}, []Wildcard{{Name: "id"}}},
+		{"is:id;", []string{"is"}, []Wildcard{{Name: "id"}}},
+		{"is:id;really", []string{"is", "really"}, []Wildcard{{Name: "id"}}},
+		{"is|was:[2,4]id;.:ext", []string{"is|was", "."}, []Wildcard{{Name: "id", Min: 2, Max: 4}, {Name: "ext"}}},
+		{"a:;b", []string{"a", "b"}, []Wildcard{{}}},
+		{"literal", []string{"literal"}, []Wildcard{}},
+	}
+
+	for _, c := range cases {
+		padding, wildcards := DebugSegment(c.pattern)
+		if !reflect.DeepEqual(padding, c.padding) || !reflect.DeepEqual(wildcards, c.wildcards) {
+			t.Errorf("%s: (actual) %q %v != %q %v (expected)", c.pattern, padding, wildcards, c.padding, c.wildcards)
+		}
+	}
+}
+
+func TestFirstSegmentPadding(t *testing.T) {
+	n := New()
+
+	n.Add("/:id;really", 1)
+	n.Add("/is:id;", 2)
+	n.Add("/is:id;really/x", 3)
+
+	found(t, n, "/isreally/x", []string{""}, 3)
+	found(t, n, "/isthisreally/x", []string{"this"}, 3)
+	found(t, n, "/isthis", []string{"this"}, 2)
+	found(t, n, "/is", []string{""}, 2)
+	found(t, n, "/thisreally", []string{"this"}, 1)
+	found(t, n, "/really", []string{""}, 1)
+	notfound(t, n, "/xisthisreally/x")
+	notfound(t, n, "/this")
+}
+
+func TestLeadingPaddingAnchored(t *testing.T) {
+	n := New(WithCaseInsensitive())
+
+	n.Add("/Archive_:first;_all", 1)
+	n.Add("/History_|Log_:first;", 2)
+	n.Add("/x/:prefix;Archive_:first;_all", 3)
+
+	found(t, n, "/Archive_March_all", []string{"March"}, 1)
+	found(t, n, "/archive_March_all", []string{"March"}, 1)
+	found(t, n, "/Log_May", []string{"May"}, 2)
+	found(t, n, "/x/XArchive_March_all", []string{"X", "March"}, 3)
+	found(t, n, "/x/Archive_March_all", []string{"", "March"}, 3)
+	notfound(t, n, "/XArchive_March_all")
+	notfound(t, n, "/ Archive_March_all")
+	notfound(t, n, "/_Archive_March_all")
+	notfound(t, n, "/XHistory_May")
+	notfound(t, n, "/BLog_May")
+}
+
+func TestTrailingPaddingAnchored(t *testing.T) {
+	n := New()
+
+	n.Add("/:[2,3]code;_suffix", 1)
+	n.Add("/v/:a;-:[1,2]b;.json", 2)
+	n.Add("/u", 3)
+
+	found(t, n, "/ab_suffix", []string{"ab"}, 1)
+	found(t, n, "/abc_suffix", []string{"abc"}, 1)
+	found(t, n, "/v/x-12.json", []string{"x", "12"}, 2)
+	found(t, n, "/u", nil, 3)
+	notfound(t, n, "/abcd_suffix")
+	notfound(t, n, "/a_suffix")
+	notfound(t, n, "/ab_suffix_suffix")
+	notfound(t, n, "/ab_suffixes")
+	notfound(t, n, "/v/x-123.json")
+	notfound(t, n, "/v/x-12.json.gz")
+	notfound(t, n, "/users")
+}
+
+func TestOptional(t *testing.T) {
+	n := New()
+
+	leaf, err := n.Add("/blog/:year/:[2]month?/:[2]day?", 1)
+	if err != nil || leaf.Pattern() != "/blog/:year/:[2]month/:[2]day" || leaf.Source() != "/blog/:year/:[2]month?/:[2]day?" {
+		t.Fatalf("Add (actual) %v %v", leaf, err)
+	}
+	n.Add("/blog/:year/:[2]month/:[2]day/:slug", 2)
+	n.Add("/docs/:page?/", 3)
+	n.Add("/files/:a;-:b?", 4)
+
+	found(t, n, "/blog/2024", []string{"2024"}, 1)
+	found(t, n, "/blog/2024/05", []string{"2024", "05"}, 1)
+	found(t, n, "/blog/2024/05/13", []string{"2024", "05", "13"}, 1)
+	found(t, n, "/blog/2024/05/13/hello", []string{"2024", "05", "13", "hello"}, 2)
+	found(t, n, "/docs/", nil, 3)
+	found(t, n, "/docs/intro/", []string{"intro"}, 3)
+	found(t, n, "/files/x-y", []string{"x", "y"}, 4)
+	found(t, n, "/files", nil, 4)
+	notfound(t, n, "/blog")
+	notfound(t, n, "/blog/2024/5")
+	notfound(t, n, "/blog/2024/05/1")
+
+	// Each number of optional elements has a leaf of its own
+	if short := n.Get("/blog/:year"); short == nil || short == leaf || short.Value != 1 || short.Source() != leaf.Source() {
+		t.Errorf("Leaf without the optional elements (actual) %v", short)
+	}
+	if n.Get("/blog/:year/:[2]month?/:[2]day?") != leaf {
+		t.Errorf("Get of the pattern with optional elements should give its leaf")
+	}
+	if path, _, err := n.Reverse(n.Get("/blog/:year/:[2]month"), map[string]string{"year": "2024", "month": "05"}); err != nil || path != "/blog/2024/05" {
+		t.Errorf("Reverse (actual) %s %v", path, err)
+	}
+
+	// Nothing is added if one of the leafs exists
+	var dup *DuplicatePathError
+	if err := n.CanAdd("/:section/:page?"); err != nil {
+		t.Errorf("CanAdd (actual) %v", err)
+	}
+	n.Add("/x", 5)
+	if err := n.CanAdd("/x/:page?"); !errors.As(err, &dup) || dup.Existing != "/x" {
+		t.Errorf("CanAdd of a duplicate prefix (actual) %v", err)
+	}
+	if _, err := n.Add("/x/:page?", 6); !errors.As(err, &dup) || dup.Pattern != "/x/:page?" || n.Get("/x/:page") != nil {
+		t.Errorf("Add of a duplicate prefix (actual) %v", err)
+	}
+
+	for _, pattern := range []string{"/a/:b?/c", "/a/:b?/:c", "/*rest/:a?"} {
+		if _, err := n.Add(pattern, 7); err == nil {
+			t.Errorf("%s: Expected an error", pattern)
+		}
+	}
+
+	// Literals can't be optional, and neither can a star
+	n.Add("/what?", 8)
+	n.Add("/q/*rest?", 9)
+	found(t, n, "/what?", nil, 8)
+	notfound(t, n, "/what")
+	notfound(t, n, "/q")
+
+	// After ';' a '?' is padding
+	n = New()
+	n.Add("/q/:a;?", 1)
+	n.Add("/r/:a;x?", 2)
+	found(t, n, "/q/x?", []string{"x"}, 1)
+	found(t, n, "/r/yx?", []string{"y"}, 2)
+	notfound(t, n, "/q")
+	notfound(t, n, "/q/x")
+	notfound(t, n, "/r/yx")
+}
+
+func TestRemove(t *testing.T) {
+	n := New()
+
+	n.Add("/a/b/c", 1)
+	n.Add("/a/:x", 2)
+	n.Add("/a/*rest", 3)
+	n.AddVersioned("/v", 4, 1, 2)
+	n.Add("/v", 5)
+
+	if leaf, err := n.Remove("/a/b/c/"); err != nil || leaf.Value != 1 {
+		t.Errorf("Remove (actual) %v %v", leaf, err)
+	}
+	if _, err := n.Remove("/a/b/c"); err == nil {
+		t.Errorf("Expected an error removing twice")
+	}
+	if _, ok := n.edges["a"].node.edges["b"]; ok {
+		t.Errorf("Empty nodes were not pruned")
+	}
+	found(t, n, "/a/b/c", []string{"b/c"}, 3)
+
+	n.Remove("/a/*other")
+	found(t, n, "/a/b", []string{"b"}, 2)
+	notfound(t, n, "/a/b/c")
+
+	n.Remove("/v")
+	notfound(t, n, "/v")
+	if leaf, _ := n.FindVersion("/v", 1); leaf == nil || leaf.Value != 4 {
+		t.Errorf("Versions should be kept when removing a path")
+	}
+
+	n.Compact()
+	n.Add("/x/y/z", 6)
+	n.Compact()
+	if leaf := n.Get("/x/y/z"); leaf == nil || leaf.Value != 6 {
+		t.Errorf("Get of compacted path (actual) %v", leaf)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	n := New()
+
+	l1, _ := n.Add("/:color(red|green|blue);", 1)
+	l2, _ := n.Add("/shirt_:[3,4]size(S|M|L|XXXL|XXXXL);_:color(red|blue)", 2)
+	n.Add("/:other", 3)
+
+	found(t, n, "/red", []string{"red"}, 1)
+	found(t, n, "/blue/", []string{"blue"}, 1)
+	found(t, n, "/yellow", []string{"yellow"}, 3)
+	found(t, n, "/shirt_XXXL_red", []string{"XXXL", "red"}, 2)
+	found(t, n, "/shirt_M_red", []string{"shirt_M_red"}, 3)
+	found(t, n, "/shirt_XXXXL_red", []string{"shirt_XXXXL_red"}, 3)
+	found(t, n, "/shirt_XXXL_green", []string{"shirt_XXXL_green"}, 3)
+
+	if l1.Wildcards[0].Name != "color" || !reflect.DeepEqual(l1.Wildcards[0].Enum, []string{"red", "green", "blue"}) {
+		t.Errorf("Wildcard (actual) %+v", l1.Wildcards[0])
+	}
+
+	reverse(t, n, l1, map[string]string{"color": "green"}, "/green", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{"color": "pink"}, "/", map[string]string{"color": "pink"}, []string{"[0,0]color"})
+	reverse(t, n, l2, map[string]string{"size": "XXXL", "color": "green"}, "/shirt_XXXL_", map[string]string{"color": "green"}, []string{"[0,0]color"})
+}
+
+func TestCanAdd(t *testing.T) {
+	n := New(WithMaxDepth(3))
+	n.Add("/users/:id", 1)
+	n.Add("/files/*path", 2)
+	n.Add("/a/b/c", 3)
+	n.Compact()
+	dump := n.String()
+
+	for _, pattern := range []string{"/users/:id", "/users/:id/", "/files/*path", "/files/*other", "/a/b/c", "users", "/a/b/c/d", "/:[2,x]y", "/users/:id;"} {
+		_, expected := n.Clone().Add(pattern, 0)
+		if err := n.CanAdd(pattern); err == nil || expected == nil || err.Error() != expected.Error() {
+			t.Errorf("%s: (actual) %v != %v (expected)", pattern, err, expected)
+		}
+	}
+	for _, pattern := range []string{"/users/:name/x", "/a/b", "/a/b/d", "/files", "/"} {
+		if err := n.CanAdd(pattern); err != nil {
+			t.Errorf("%s: Unexpected error: %v", pattern, err)
+		}
+	}
+	if n.String() != dump {
+		t.Errorf("CanAdd changed the tree:\n%s", n)
+	}
+}
+
+func TestSetOnAdd(t *testing.T) {
+	n := New()
+	var added []string
+	n.SetOnAdd(func(pattern string, leaf *Leaf) {
+		added = append(added, fmt.Sprintf("%s=%v", pattern, leaf.Value))
+	})
+
+	n.Add("/users/:id", 1)
+	n.Add("/users/:id", 2)
+	n.Add("/files/*path", 3)
+	n.AddIf("/beta", 4, func() bool { return true })
+	if expected := []string{"/users/:id=1", "/files/*path=3", "/beta=4"}; !reflect.DeepEqual(added, expected) {
+		t.Errorf("Added (actual) %v != %v (expected)", added, expected)
+	}
+
+	n.SetOnAdd(nil)
+	if _, err := n.Add("/x", 5); err != nil || len(added) != 3 {
+		t.Errorf("Add without a hook (actual) %v %v", err, added)
+	}
+}
+
+func TestAnonymousStar(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/x/*", 1)
+	l2, _ := n.Add("/y/:a/*", 2)
+
+	if l1.Wildcards[0].Name != "*1" || l2.Wildcards[1].Name != "*2" {
+		t.Errorf("Star names (actual) %v %v", l1.Wildcards, l2.Wildcards)
+	}
+	if l1.Pattern() != "/x/*" || l2.Pattern() != "/y/:a/*" {
+		t.Errorf("Patterns (actual) %s %s", l1.Pattern(), l2.Pattern())
+	}
+	if m := n.Match("/x/a/b"); m == nil || m.Leaf != l1 || !reflect.DeepEqual(m.Params(), map[string]string{"*1": "a/b"}) {
+		t.Errorf("Match(/x/a/b) (actual) %v", m)
+	}
+	if m := n.Match("/y/q/r"); m == nil || m.Leaf != l2 || !reflect.DeepEqual(m.Params(), map[string]string{"a": "q", "*2": "r"}) {
+		t.Errorf("Match(/y/q/r) (actual) %v", m)
+	}
+
+	reverse(t, n, l1, map[string]string{"*1": "a/b"}, "/x/a/b", map[string]string{}, nil)
+	reverse(t, n, l2, map[string]string{"a": "q", "*2": "r"}, "/y/q/r", map[string]string{}, nil)
+	reverse(t, n, l1, map[string]string{"*2": "r"}, "/x", map[string]string{"*2": "r"}, []string{"[0,0]*1"})
+
+	if _, err := n.Add("/z/**a", 3); err == nil {
+		t.Errorf("Expected an error for a star name beginning with *")
+	}
+}
+
+func TestSource(t *testing.T) {
+	n := New()
+	l1, _ := n.Add("/Archive_:first;_:year;/", 1)
+	l2, _ := n.Alias("/Archive_:first;_:year;/", "/a/:first/:year;")
+	l3, _ := n.AddVersioned("/v/:id;", 3, 1, 2)
+
+	if l1.Source() != "/Archive_:first;_:year;/" || l1.Pattern() != "/Archive_:first;_:year/" {
+		t.Errorf("Source (actual) %s, pattern %s", l1.Source(), l1.Pattern())
+	}
+	if l2.Source() != "/a/:first/:year;" || l3.Source() != "/v/:id;" {
+		t.Errorf("Sources (actual) %s %s", l2.Source(), l3.Source())
+	}
+	if c := n.Clone(); c.Get("/Archive_:first;_:year").Source() != l1.Source() {
+		t.Errorf("Clone lost the source")
+	}
+
+	_, err := n.Add("/Archive_:first;_:year", 2)
+	var dup *DuplicatePathError
+	if !errors.As(err, &dup) || dup.Pattern != "/Archive_:first;_:year" || dup.Existing != l1.Source() || err.Error() != `duplicate path "/Archive_:first;_:year": already added as "/Archive_:first;_:year;/"` {
+		t.Errorf("Duplicate path error (actual) %#v", err)
+	}
+
+	moved, _ := n.Move("/Archive_:first;_:year/", "/archive/:first;/:year;", false)
+	if moved.Source() != "/archive/:first;/:year;" {
+		t.Errorf("Source after Move (actual) %s", moved.Source())
+	}
+
+	data, _ := n.MarshalJSON()
+	c := New()
+	if _, err := c.ImportJSON(data); err != nil || c.Get("/archive/:first/:year").Source() != "/archive/:first;/:year;" {
+		t.Errorf("ImportJSON lost the source: %v", err)
+	}
+}
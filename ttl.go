@@ -0,0 +1,38 @@
+package pathtree
+
+import "time"
+
+// AddTTL adds a path like Add that Find ignores once ttl has passed, as told
+// by now, or time.Now if nil. Expired leafs are only removed from the tree by
+// Sweep.
+func (n *Node) AddTTL(key string, val interface{}, ttl time.Duration, now func() time.Time) (leaf *Leaf, err error) {
+	if now == nil {
+		now = time.Now
+	}
+	if leaf, err = n.Add(key, val); err == nil {
+		leaf.expires, leaf.now = now().Add(ttl), now
+	}
+	return leaf, err
+}
+
+// Expired reports if the leaf was added with AddTTL and its ttl has passed.
+func (l *Leaf) Expired() bool {
+	return l.now != nil && !l.now().Before(l.expires)
+}
+
+// Sweep removes the expired leafs from the tree, pruning the nodes left empty,
+// and returns how many were removed. Like Add and Remove it changes the tree,
+// so it must not run concurrently with Find or other lookups.
+func (n *Node) Sweep() int {
+	var expired []string
+	n.walk(func(l *Leaf) {
+		if l.Expired() {
+			expired = append(expired, l.Pattern())
+		}
+	})
+
+	for _, pattern := range expired {
+		n.Remove(pattern)
+	}
+	return len(expired)
+}
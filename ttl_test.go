@@ -0,0 +1,42 @@
+package pathtree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTL(t *testing.T) {
+	now := time.Date(2013, 4, 5, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	n := New()
+	l2, _ := n.AddTTL("/callbacks/:id", 2, 15*time.Minute, clock)
+	n.Add("/callbacks/*rest", 1)
+	n.AddTTL("/tmp/a/b", 3, time.Hour, clock)
+
+	found(t, n, "/callbacks/7", []string{"7"}, 2)
+	found(t, n, "/tmp/a/b", nil, 3)
+
+	now = now.Add(15 * time.Minute)
+	if !l2.Expired() {
+		t.Errorf("Leaf should have expired")
+	}
+	found(t, n, "/callbacks/7", []string{"7"}, 1)
+	found(t, n, "/tmp/a/b", nil, 3)
+
+	if removed := n.Sweep(); removed != 1 {
+		t.Errorf("Sweep (actual) %d != 1 (expected)", removed)
+	}
+	if n.Get("/callbacks/:id") != nil {
+		t.Errorf("Sweep should have removed /callbacks/:id")
+	}
+
+	now = now.Add(time.Hour)
+	notfound(t, n, "/tmp/a/b")
+	if removed := n.Sweep(); removed != 1 || n.edges["tmp"] != nil {
+		t.Errorf("Sweep should have removed and pruned /tmp/a/b (actual) %d", removed)
+	}
+	if removed := n.Sweep(); removed != 0 {
+		t.Errorf("Sweep of a tree without expired leafs (actual) %d", removed)
+	}
+}
@@ -0,0 +1,87 @@
+package pathtree
+
+import (
+	"errors"
+	"strings"
+)
+
+// Typed is a path tree holding values of type T, wrapping a Node so values
+// don't need type assertions. The Node is available for everything else.
+type Typed[T any] struct {
+	Node *Node
+}
+
+// NewTyped returns a new typed path tree.
+func NewTyped[T any]() *Typed[T] {
+	return &Typed[T]{Node: New()}
+}
+
+// Add a path and its associated value to the tree, like Node.Add.
+func (t *Typed[T]) Add(key string, val T) (*Leaf, error) {
+	return t.Node.Add(key, val)
+}
+
+// Find a given path like Node.Find. ok is false if nothing was found.
+func (t *Typed[T]) Find(key string) (val T, expansions []string, ok bool) {
+	leaf, expansions := t.Node.Find(key)
+	if leaf == nil {
+		return val, nil, false
+	}
+	return Value[T](leaf), expansions, true
+}
+
+// Walk calls fn with the pattern and value of every leaf in the tree.
+func (t *Typed[T]) Walk(fn func(pattern string, val T)) {
+	t.Node.Walk(func(leaf *Leaf) {
+		fn(leaf.Pattern(), Value[T](leaf))
+	})
+}
+
+// Get returns the value added with the given pattern, like Node.Get.
+func (t *Typed[T]) Get(pattern string) (val T, ok bool) {
+	leaf := t.Node.Get(pattern)
+	if leaf == nil {
+		return val, false
+	}
+	return Value[T](leaf), true
+}
+
+// Update replaces the value added with the given pattern, like Node.Update.
+func (t *Typed[T]) Update(pattern string, val T) error {
+	_, err := t.Node.Update(pattern, val)
+	return err
+}
+
+// Remove removes the given pattern and returns its value, like Node.Remove.
+func (t *Typed[T]) Remove(pattern string) (val T, err error) {
+	leaf, err := t.Node.Remove(pattern)
+	if err != nil {
+		return val, err
+	}
+	return Value[T](leaf), nil
+}
+
+// Reverse the leaf added with the given pattern into a path, like
+// Node.Reverse. Returns an error listing the missing wildcards if any.
+func (t *Typed[T]) Reverse(pattern string, vars map[string]string) (string, error) {
+	leaf := t.Node.Get(pattern)
+	if leaf == nil {
+		return "", errors.New("path not found")
+	}
+
+	copied := make(map[string]string, len(vars))
+	for key, value := range vars {
+		copied[key] = value
+	}
+	path, _, missing := t.Node.Reverse(leaf, copied)
+	if len(missing) > 0 {
+		return "", errors.New("missing " + strings.Join(missing, ", "))
+	}
+	return path, nil
+}
+
+// Value returns the value of a leaf as a T, or the zero value if it isn't one.
+func Value[T any](leaf *Leaf) T {
+	val, _ := leaf.Load().(T)
+	return val
+}
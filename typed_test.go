@@ -0,0 +1,51 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTyped(t *testing.T) {
+	n := NewTyped[int]()
+
+	n.Add("/users/:id", 1)
+	n.Add("/files/*path", 2)
+
+	val, expansions, ok := n.Find("/users/7")
+	if !ok || val != 1 || !reflect.DeepEqual(expansions, []string{"7"}) {
+		t.Errorf("Find (actual) %v %v %v", val, expansions, ok)
+	}
+	if _, _, ok := n.Find("/missing/x/y"); ok {
+		t.Errorf("Should not have found: /missing/x/y")
+	}
+
+	if err := n.Update("/users/:id", 3); err != nil {
+		t.Errorf("Update failed: %v", err)
+	}
+	if val, ok := n.Get("/users/:id"); !ok || val != 3 {
+		t.Errorf("Get (actual) %v %v", val, ok)
+	}
+
+	walked := make(map[string]int)
+	n.Walk(func(pattern string, val int) { walked[pattern] = val })
+	if !reflect.DeepEqual(walked, map[string]int{"/users/:id": 3, "/files/*path": 2}) {
+		t.Errorf("Walk (actual) %v", walked)
+	}
+
+	if path, err := n.Reverse("/files/*path", map[string]string{"path": "a/b"}); err != nil || path != "/files/a/b" {
+		t.Errorf("Reverse (actual) %v %v", path, err)
+	}
+	if _, err := n.Reverse("/users/:id", nil); err == nil {
+		t.Errorf("Expected an error for a missing wildcard")
+	}
+
+	if val, err := n.Remove("/users/:id"); err != nil || val != 3 {
+		t.Errorf("Remove (actual) %v %v", val, err)
+	}
+	if _, _, ok := n.Find("/users/7"); ok {
+		t.Errorf("Should not have found removed path: /users/7")
+	}
+	if err := n.Update("/users/:id", 4); err == nil {
+		t.Errorf("Expected an error updating a removed path")
+	}
+}
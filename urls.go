@@ -0,0 +1,54 @@
+package pathtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// URLError reports a variable map that could not be reversed into a URL.
+type URLError struct {
+	Leaf      *Leaf             // the leaf being reversed
+	Variables map[string]string // the variables given for it
+	Missing   []string          // the wildcards missing or violating constraints
+}
+
+func (e *URLError) Error() string {
+	return "cannot reverse " + e.Leaf.Pattern() + ": missing " + strings.Join(e.Missing, ", ")
+}
+
+// URLs reverses every leaf in the tree into concrete URLs, eg. for a sitemap.
+// For each leaf, provider returns the variable maps to reverse it with. Leafs
+// without wildcards are reversed once when provider returns nil for them.
+//
+// The URLs are returned sorted and without duplicates. Maps that don't supply
+// every wildcard, or that violate their constraints, are skipped and reported
+// as *URLError.
+func (n *Node) URLs(provider func(*Leaf) []map[string]string) (urls []string, errs []error) {
+	seen := make(map[string]bool)
+	n.Walk(func(leaf *Leaf) {
+		maps := provider(leaf)
+		if maps == nil && len(leaf.Wildcards) == 0 {
+			maps = []map[string]string{nil}
+		}
+
+		for _, vars := range maps {
+			copied := make(map[string]string, len(vars))
+			for key, value := range vars {
+				copied[key] = value
+			}
+
+			path, _, missing := n.Reverse(leaf, copied)
+			if len(missing) > 0 {
+				errs = append(errs, &URLError{leaf, vars, missing})
+				continue
+			}
+			if !seen[path] {
+				seen[path] = true
+				urls = append(urls, path)
+			}
+		}
+	})
+
+	sort.Strings(urls)
+	return urls, errs
+}
@@ -0,0 +1,36 @@
+package pathtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestURLs(t *testing.T) {
+	n := New()
+
+	n.Add("/", 1)
+	n.Add("/about/", 2)
+	n.Add("/posts/:[1,10]slug", 3)
+	n.Add("/files/*path", 4)
+	n.Add("/alias", 5)
+
+	urls, errs := n.URLs(func(leaf *Leaf) []map[string]string {
+		switch leaf.Value {
+		case 3:
+			return []map[string]string{{"slug": "hello"}, {"slug": "a-very-long-slug"}, {"slug": "hello"}}
+		case 4:
+			return []map[string]string{{"path": "a/b.css"}}
+		case 5:
+			return []map[string]string{}
+		}
+		return nil
+	})
+
+	expected := []string{"/", "/about/", "/files/a/b.css", "/posts/hello"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("URLs (actual) %v != %v (expected)", urls, expected)
+	}
+	if len(errs) != 1 || errs[0].Error() != "cannot reverse /posts/:[1,10]slug: missing [1,10]slug" {
+		t.Errorf("Errors (actual) %v", errs)
+	}
+}
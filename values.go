@@ -0,0 +1,139 @@
+package pathtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// LeafValueMarshaler is implemented by leaf values that MarshalJSON exports so
+// ImportJSON can restore them, using the decoder registered for their type
+// with RegisterValueType.
+type LeafValueMarshaler interface {
+	MarshalLeafValue() ([]byte, error)
+}
+
+// EncodedValue is a leaf value as MarshalJSON exports it, unless it's a
+// string, number, bool or nil which are exported as they are. Values that
+// aren't a LeafValueMarshaler are exported as a placeholder with their type.
+type EncodedValue struct {
+	Type        string `json:"type"`                  // the type of the value, as printed by %T
+	Data        []byte `json:"data,omitempty"`        // the value as marshaled by MarshalLeafValue
+	Placeholder bool   `json:"placeholder,omitempty"` // if the value isn't a LeafValueMarshaler
+}
+
+// Decodes a leaf value marshaled by MarshalLeafValue.
+type valueDecoder func([]byte) (interface{}, error)
+
+// RegisterValueType registers the decoder ImportJSON uses for values of the
+// type named name, as printed by %T, eg. "*main.Handler".
+func (n *Node) RegisterValueType(name string, decode func([]byte) (interface{}, error)) {
+	if n.conf.decoders == nil {
+		n.conf.decoders = make(map[string]valueDecoder)
+	}
+	n.conf.decoders[name] = decode
+}
+
+// Returns the value as MarshalJSON exports it.
+func encodeValue(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case nil, string, bool, json.Number,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, nil
+	}
+
+	name := fmt.Sprintf("%T", v)
+	m, ok := v.(LeafValueMarshaler)
+	if !ok {
+		return EncodedValue{Type: name, Placeholder: true}, nil
+	}
+	data, err := m.MarshalLeafValue()
+	if err != nil {
+		return nil, err
+	}
+	return EncodedValue{Type: name, Data: data}, nil
+}
+
+// Returns the value exported by MarshalJSON as raw, or nil and a warning if it
+// can't be restored.
+func (c *config) decodeValue(raw json.RawMessage) (v interface{}, warning string, err error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		err = json.Unmarshal(raw, &v)
+		return v, "", err
+	}
+
+	var encoded EncodedValue
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, "", err
+	}
+	decode, ok := c.decoders[encoded.Type]
+	switch {
+	case encoded.Placeholder:
+		return nil, "value of type " + encoded.Type + " wasn't exported", nil
+	case !ok:
+		return nil, "no decoder registered for type " + encoded.Type, nil
+	}
+	v, err = decode(encoded.Data)
+	return v, "", err
+}
+
+// ImportJSON adds the routes exported by MarshalJSON to the tree, keeping their
+// priorities, versions, aliases and tags. Values are restored as MarshalJSON
+// exported them, numbers as float64, and LeafValueMarshalers with the decoders
+// registered with RegisterValueType. Values that can't be restored are nil,
+// with a warning for each. Routes that can't be added are left out and their
+// errors returned together.
+func (n *Node) ImportJSON(data []byte) (warnings []string, err error) {
+	var routes []struct {
+		Route
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Order < routes[j].Order })
+
+	// Aliases are added once all the leafs they can be an alias of are
+	var errs []error
+	for _, aliases := range []bool{false, true} {
+		for _, route := range routes {
+			if (route.Alias != "") != aliases {
+				continue
+			}
+
+			var leaf *Leaf
+			var err error
+			switch {
+			case aliases:
+				leaf, err = n.Alias(route.Alias, route.Pattern)
+			case len(route.Versions) == 2:
+				leaf, err = n.AddVersioned(route.Pattern, nil, route.Versions[0], route.Versions[1])
+			default:
+				leaf, err = n.Add(route.Pattern, nil)
+			}
+			if err != nil {
+				errs = append(errs, &PatternError{route.Pattern, err})
+				continue
+			}
+
+			if !aliases && route.Value != nil {
+				value, warning, err := n.conf.decodeValue(route.Value)
+				if err != nil {
+					errs = append(errs, &PatternError{route.Pattern, err})
+				} else if warning != "" {
+					warnings = append(warnings, route.Pattern+": "+warning)
+				}
+				leaf.Value = value
+			}
+			if route.Source != "" {
+				leaf.source = route.Source
+			}
+			for _, tag := range route.Tags {
+				leaf.AddTag(tag)
+			}
+		}
+	}
+	return warnings, errors.Join(errs...)
+}
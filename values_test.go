@@ -0,0 +1,83 @@
+package pathtree
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type handler struct {
+	name string
+}
+
+func (h *handler) MarshalLeafValue() ([]byte, error) {
+	if h.name == "" {
+		return nil, errors.New("unnamed handler")
+	}
+	return []byte(h.name), nil
+}
+
+func TestImportJSON(t *testing.T) {
+	n := New()
+	n.Add("/", "home")
+	l, _ := n.Add("/users/:id", &handler{"user"})
+	l.AddTag("admin")
+	n.Add("/files/*path", 7)
+	n.Add("/opaque", struct{ x int }{1})
+	n.AddVersioned("/users/:id", true, 1, 2)
+	n.Alias("/users/:id", "/u/:id")
+
+	data, err := n.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"value":{"type":"*pathtree.handler","data":"dXNlcg=="}`) {
+		t.Errorf("MarshalJSON should encode LeafValueMarshalers (actual) %s", data)
+	}
+
+	i := New()
+	i.RegisterValueType("*pathtree.handler", func(data []byte) (interface{}, error) {
+		return &handler{string(data)}, nil
+	})
+	warnings, err := i.ImportJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(warnings, []string{"/opaque: value of type struct { x int } wasn't exported"}) {
+		t.Errorf("ImportJSON warnings (actual) %v", warnings)
+	}
+
+	if leaf, _ := i.Find("/users/7"); leaf == nil || !reflect.DeepEqual(leaf.Value, &handler{"user"}) || !leaf.HasTag("admin") {
+		t.Errorf("ImportJSON user (actual) %v", leaf)
+	}
+	if leaf, _ := i.FindVersion("/users/7", 2); leaf == nil || leaf.Value != true {
+		t.Errorf("ImportJSON version (actual) %v", leaf)
+	}
+	if leaf, _ := i.Find("/u/7"); leaf == nil || leaf.Pattern() != "/users/:id" {
+		t.Errorf("ImportJSON alias (actual) %v", leaf)
+	}
+	if leaf, _ := i.Find("/files/a"); leaf == nil || leaf.Value != 7.0 {
+		t.Errorf("ImportJSON number (actual) %v", leaf)
+	}
+	if leaf, _ := i.Find("/opaque"); leaf == nil || leaf.Value != nil {
+		t.Errorf("ImportJSON placeholder (actual) %v", leaf)
+	}
+	if leafs := i.LeavesByPriority(); len(leafs) != 6 || leafs[0].Value != "home" || leafs[5].Pattern() != "/opaque" {
+		t.Errorf("ImportJSON should keep priorities (actual) %v", leafs)
+	}
+
+	if warnings, _ := New().ImportJSON(data); len(warnings) != 2 {
+		t.Errorf("ImportJSON without decoder (actual) %v", warnings)
+	}
+	if _, err := i.ImportJSON(data); err == nil {
+		t.Errorf("Expected duplicate path errors")
+	}
+	if _, err := New().MarshalJSON(); err != nil {
+		t.Error(err)
+	}
+	n.Add("/broken", &handler{})
+	if _, err := n.MarshalJSON(); err == nil || err.Error() != "/broken: unnamed handler" {
+		t.Errorf("Expected an error marshaling a value (actual) %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package pathtree
+
+import (
+	"errors"
+	"strconv"
+)
+
+// AddVersioned adds a path whose leaf is only found by FindVersion for versions
+// from minVer to maxVer inclusive. The same path may be added for several
+// version ranges, and with Add for versions without a range of their own, but
+// the ranges of a path may not overlap. Find ignores versioned leafs.
+func (n *Node) AddVersioned(key string, val interface{}, minVer, maxVer int) (leaf *Leaf, err error) {
+	if minVer > maxVer {
+		return nil, errors.New("invalid version range " + versionRange(minVer, maxVer))
+	}
+	p, err := n.parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the ranges of the path already added before changing the tree
+	if slot := n.slotElements(p.elements); slot != nil && *slot != nil {
+		for _, version := range (*slot).versions {
+			if minVer <= version.maxver && version.minver <= maxVer {
+				return nil, errors.New("version range " + versionRange(minVer, maxVer) + " overlaps " + versionRange(version.minver, version.maxver))
+			}
+		}
+	}
+
+	n.leafs++
+	slot, head := n.add(n.leafs, p, 0, nil)
+	if *slot == nil {
+		head.hidden, head.source = true, key
+		*slot = head
+	}
+	head = *slot
+
+	leaf = &Leaf{
+		Value:     val,
+		Wildcards: head.Wildcards,
+		order:     head.order,
+		parent:    head.parent,
+		slashend:  head.slashend,
+		pattern:   head.pattern,
+		source:    key,
+		head:      head,
+		minver:    minVer,
+		maxver:    maxVer,
+	}
+	head.versions = append(head.versions, leaf)
+	return leaf, nil
+}
+
+// FindVersion finds a given path like Find, using the leaf whose version range
+// contains version for each path, or else the leaf added without a range.
+func (n *Node) FindVersion(key string, version int) (leaf *Leaf, expansions []string) {
+	elements, slashend, ok := n.lookup(key)
+	if !ok {
+		return nil, nil
+	}
+
+	q := &query{strict: n.conf.strict, slashend: slashend, pick: func(l *Leaf) *Leaf {
+		for _, v := range l.versions {
+			if v.minver <= version && version <= v.maxver {
+				return v
+			}
+		}
+		if l.hidden {
+			return nil
+		}
+		return l
+	}}
+	return n.find(elements, nil, q)
+}
+
+// Versions returns the version range of a leaf added with AddVersioned.
+func (l *Leaf) Versions() (minVer, maxVer int, ok bool) {
+	return l.minver, l.maxver, l.head != nil
+}
+
+func versionRange(minVer, maxVer int) string {
+	return "[" + strconv.Itoa(minVer) + "," + strconv.Itoa(maxVer) + "]"
+}
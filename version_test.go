@@ -0,0 +1,67 @@
+package pathtree
+
+import (
+	"testing"
+)
+
+func foundVersion(t *testing.T, n *Node, p string, version int, val interface{}) {
+	leaf, _ := n.FindVersion(p, version)
+	if leaf == nil {
+		if val != nil {
+			t.Errorf("%s@%d: Didn't find", p, version)
+		}
+		return
+	}
+	if leaf.Value != val {
+		t.Errorf("%s@%d: Value (actual) %v != %v (expected)", p, version, leaf.Value, val)
+	}
+}
+
+func TestVersioned(t *testing.T) {
+	n := New()
+
+	n.AddVersioned("/users/:id", "v1", 1, 1)
+	n.AddVersioned("/users/:id", "v2-3", 2, 3)
+	n.Add("/users/:id", "default")
+	n.AddVersioned("/beta/*rest", "beta", 3, 5)
+	n.Add("/:section/:id", "fallback")
+
+	leafs, nodes := n.leafs, n.nodes()
+	if _, err := n.AddVersioned("/users/:id", "overlap", 3, 4); err == nil {
+		t.Errorf("Expected an error for overlapping version ranges")
+	}
+	if n.leafs != leafs || n.nodes() != nodes {
+		t.Errorf("A rejected range changed the tree (actual) %d leafs %d nodes != %d %d (expected)", n.leafs, n.nodes(), leafs, nodes)
+	}
+	if _, err := n.AddVersioned("/users/:id", "inverted", 6, 5); err == nil {
+		t.Errorf("Expected an error for an inverted version range")
+	}
+	if _, err := n.Add("/users/:id", "again"); err == nil {
+		t.Errorf("Expected duplicate path error")
+	}
+
+	foundVersion(t, n, "/users/7", 1, "v1")
+	foundVersion(t, n, "/users/7", 3, "v2-3")
+	foundVersion(t, n, "/users/7", 4, "default")
+	foundVersion(t, n, "/beta/x/y", 4, "beta")
+	foundVersion(t, n, "/beta/x", 2, "fallback")
+	foundVersion(t, n, "/beta/x/y", 2, nil)
+
+	found(t, n, "/users/7", []string{"7"}, "default")
+	found(t, n, "/beta/x", []string{"beta", "x"}, "fallback")
+	notfound(t, n, "/beta/x/y")
+
+	leaf, expansions := n.FindVersion("/beta/x/y", 5)
+	if leaf == nil || len(expansions) != 1 || expansions[0] != "x/y" || leaf.Pattern() != "/beta/*rest" {
+		t.Errorf("Versioned star (actual) %v %v", leaf, expansions)
+	}
+	if min, max, ok := leaf.Versions(); !ok || min != 3 || max != 5 {
+		t.Errorf("Versions (actual) %d %d %v", min, max, ok)
+	}
+
+	count := 0
+	n.Walk(func(*Leaf) { count++ })
+	if count != 5 {
+		t.Errorf("Walked (actual) %d != 5 (expected) leafs", count)
+	}
+}
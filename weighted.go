@@ -0,0 +1,81 @@
+package pathtree
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// WeightedValue is one of the values of a leaf added with AddWeighted.
+type WeightedValue struct {
+	Value  interface{} // the value
+	Weight int         // the relative chance of choosing the value, 0 for never
+}
+
+// AddWeighted adds a path whose value is chosen randomly between choices by
+// FindWeighted, with a chance proportional to their weight. The leaf's Value is
+// that of the first choice, so Find returns it as usual.
+func (n *Node) AddWeighted(key string, choices []WeightedValue) (leaf *Leaf, err error) {
+	if len(choices) == 0 {
+		return nil, errors.New("no values to choose from")
+	}
+	for _, choice := range choices {
+		if choice.Weight < 0 {
+			return nil, errors.New("negative weight")
+		}
+	}
+
+	if leaf, err = n.Add(key, choices[0].Value); err == nil {
+		leaf.choices = append([]WeightedValue(nil), choices...)
+	}
+	return leaf, err
+}
+
+// SetWeight changes the weight of a choice of a leaf added with AddWeighted.
+// Choices with weight 0 are kept, so they can be turned back on later. It
+// changes the leaf in place, so it must not run concurrently with FindWeighted
+// or other lookups.
+func (l *Leaf) SetWeight(index, weight int) error {
+	if index < 0 || index >= len(l.choices) {
+		return errors.New("no such choice")
+	}
+	if weight < 0 {
+		return errors.New("negative weight")
+	}
+	l.choices[index].Weight = weight
+	return nil
+}
+
+// FindWeighted finds a given path like Find and chooses a value for leafs added
+// with AddWeighted using rnd, or the default source if rnd is nil. Returns the
+// value and the index of the choice, which is always 0 for other leafs. If all
+// the weights of the leaf are 0 nothing is found.
+func (n *Node) FindWeighted(key string, rnd *rand.Rand) (leaf *Leaf, expansions []string, value interface{}, index int) {
+	leaf, expansions = n.Find(key)
+	if leaf == nil {
+		return nil, nil, nil, 0
+	}
+	if leaf.choices == nil {
+		return leaf, expansions, leaf.Load(), 0
+	}
+
+	total := 0
+	for _, choice := range leaf.choices {
+		total += choice.Weight
+	}
+	if total == 0 {
+		return nil, nil, nil, 0
+	}
+
+	var pick int
+	if rnd != nil {
+		pick = rnd.Intn(total)
+	} else {
+		pick = rand.Intn(total)
+	}
+	for index = range leaf.choices {
+		if pick -= leaf.choices[index].Weight; pick < 0 {
+			break
+		}
+	}
+	return leaf, expansions, leaf.choices[index].Value, index
+}
@@ -0,0 +1,46 @@
+package pathtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFindWeighted(t *testing.T) {
+	n := New()
+
+	leaf, _ := n.AddWeighted("/checkout", []WeightedValue{{"old", 90}, {"new", 10}, {"off", 0}})
+	n.Add("/plain", "plain")
+
+	found(t, n, "/checkout", nil, "old")
+
+	rnd := rand.New(rand.NewSource(1))
+	counts := make([]int, 3)
+	for i := 0; i < 1000; i++ {
+		_, _, value, index := n.FindWeighted("/checkout", rnd)
+		if value != []string{"old", "new", "off"}[index] {
+			t.Fatalf("Value (actual) %v doesn't match choice %d", value, index)
+		}
+		counts[index]++
+	}
+	if counts[0] < 850 || counts[1] < 50 || counts[2] != 0 {
+		t.Errorf("Unexpected distribution %v", counts)
+	}
+
+	leaf.SetWeight(0, 0)
+	leaf.SetWeight(2, 1)
+	if _, _, value, index := n.FindWeighted("/checkout", rnd); value != "new" && value != "off" || index == 0 {
+		t.Errorf("Chose (actual) %v %d after changing weights", value, index)
+	}
+	leaf.SetWeight(1, 0)
+	leaf.SetWeight(2, 0)
+	if leaf, _, _, _ := n.FindWeighted("/checkout", rnd); leaf != nil {
+		t.Errorf("Should not have found a leaf without weights")
+	}
+
+	if _, _, value, index := n.FindWeighted("/plain", nil); value != "plain" || index != 0 {
+		t.Errorf("Plain (actual) %v %d", value, index)
+	}
+	if _, err := n.AddWeighted("/empty", nil); err == nil {
+		t.Errorf("Expected an error without choices")
+	}
+}